@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"context"
+	"math/big"
 	"strconv"
 	"time"
 
@@ -10,7 +12,7 @@ import (
 // EmitGetTxIDAndCheckBalance emits amount of tokens to userAddressBase58Check and checks that balance is equal to amount
 func EmitGetTxIDAndCheckBalance(
 	t provider.T,
-	hlfProxy HlfProxyService,
+	hlfProxy ChaincodeClient,
 	userAddressBase58Check string,
 	issuer Issuer,
 	channel string,
@@ -19,10 +21,11 @@ func EmitGetTxIDAndCheckBalance(
 ) string {
 	var txID string
 	t.WithNewStep("Emit "+amount+" token to user "+userAddressBase58Check+" and get txId", func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
 		emitArgs := []string{userAddressBase58Check, amount}
 		signedEmitArgs, err := Sign(issuer.IssuerEd25519PrivateKey, issuer.IssuerEd25519PublicKey, channel, chaincode, "emit", emitArgs)
 		sCtx.Require().NoError(err)
-		res, err := hlfProxy.Invoke(channel, "emit", signedEmitArgs...)
+		res, err := hlfProxy.InvokeContext(context.Background(), channel, "emit", signedEmitArgs...)
 		sCtx.Require().NoError(err)
 		time.Sleep(BatchTransactionTimeout)
 		txID = res.TransactionID
@@ -32,10 +35,33 @@ func EmitGetTxIDAndCheckBalance(
 	return txID
 }
 
+// Emit signs and invokes emission of amount of tokens to userAddressBase58Check by issuer without waiting for batch execution
+func Emit(hlfProxy ChaincodeClient, userAddressBase58Check string, issuer Issuer, channel string, chaincode string, amount string) (*Response, error) {
+	return EmitContext(context.Background(), hlfProxy, userAddressBase58Check, issuer, channel, chaincode, amount)
+}
+
+// EmitContext - Emit with context
+func EmitContext(
+	ctx context.Context,
+	hlfProxy ChaincodeClient,
+	userAddressBase58Check string,
+	issuer Issuer,
+	channel string,
+	chaincode string,
+	amount string,
+) (*Response, error) {
+	emitArgs := []string{userAddressBase58Check, amount}
+	signedEmitArgs, err := Sign(issuer.IssuerEd25519PrivateKey, issuer.IssuerEd25519PublicKey, channel, chaincode, "emit", emitArgs)
+	if err != nil {
+		return nil, err
+	}
+	return hlfProxy.InvokeContext(ctx, channel, "emit", signedEmitArgs...)
+}
+
 // EmitGetResponseAndCheckBalance emits amount of tokens to userAddressBase58Check and checks that balance is equal to amount
 func EmitGetResponseAndCheckBalance(
 	t provider.T,
-	hlfProxy HlfProxyService,
+	hlfProxy ChaincodeClient,
 	userAddressBase58Check string,
 	iss Issuer,
 	channel string,
@@ -44,10 +70,11 @@ func EmitGetResponseAndCheckBalance(
 ) *Response {
 	var res *Response
 	t.WithNewStep("Emit "+amount+" token to user "+userAddressBase58Check+" and get txId", func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
 		emitArgs := []string{userAddressBase58Check, amount}
 		signedEmitArgs, err := Sign(iss.IssuerEd25519PrivateKey, iss.IssuerEd25519PublicKey, channel, chaincode, "emit", emitArgs)
 		sCtx.Require().NoError(err)
-		res, err = hlfProxy.Invoke(channel, "emit", signedEmitArgs...)
+		res, err = hlfProxy.InvokeContext(context.Background(), channel, "emit", signedEmitArgs...)
 		sCtx.Require().NoError(err)
 		time.Sleep(BatchTransactionTimeout)
 	})
@@ -56,30 +83,58 @@ func EmitGetResponseAndCheckBalance(
 	return res
 }
 
+// GetBalance returns balance of userAddressBase58Check
+func GetBalance(hlfProxy ChaincodeClient, userAddressBase58Check string, channel string) (*big.Int, error) {
+	return QueryAmount(hlfProxy, channel, "balanceOf", userAddressBase58Check)
+}
+
+// GetAllowedBalance returns allowed balance of userAddressBase58Check in token tokenUppercase
+func GetAllowedBalance(hlfProxy ChaincodeClient, userAddressBase58Check string, channel string, tokenUppercase string) (*big.Int, error) {
+	return QueryAmount(hlfProxy, channel, "allowedBalanceOf", userAddressBase58Check, tokenUppercase)
+}
+
 // CheckBalanceEqual checks that balance of userAddressBase58Check is equal to amount
-func CheckBalanceEqual(t provider.T, hlfProxy HlfProxyService, userAddressBase58Check string, channel string, amount string) {
+func CheckBalanceEqual(t provider.T, hlfProxy ChaincodeClient, userAddressBase58Check string, channel string, amount string) {
 	t.WithNewStep("Checking that balance equal "+amount, func(sCtx provider.StepCtx) {
-		respGetBalance, err := hlfProxy.Query(channel, "balanceOf", userAddressBase58Check)
-		sCtx.Require().NoError(err)
-		sCtx.Require().Equal("\""+amount+"\"", string(respGetBalance.Payload))
+		CheckBalanceEqualInStep(sCtx, hlfProxy, userAddressBase58Check, channel, amount)
 	})
 }
 
+// CheckBalanceEqualInStep checks in current allure step that balance of userAddressBase58Check is equal to amount
+func CheckBalanceEqualInStep(sCtx provider.StepCtx, hlfProxy ChaincodeClient, userAddressBase58Check string, channel string, amount string) {
+	balance, err := GetBalance(ClientInStep(hlfProxy, sCtx), userAddressBase58Check, channel)
+	sCtx.Require().NoError(err)
+	sCtx.Require().Equal(amount, balance.String())
+}
+
 // CheckAllowedBalanceEqual checks that allowed balance of userAddressBase58Check is equal to amount
-func CheckAllowedBalanceEqual(t provider.T, hlfProxy HlfProxyService, userAddressBase58Check string, channel string, tokenUppercase string, amount string) {
+func CheckAllowedBalanceEqual(t provider.T, hlfProxy ChaincodeClient, userAddressBase58Check string, channel string, tokenUppercase string, amount string) {
 	t.WithNewStep("Checking that allowed balance equal "+amount, func(sCtx provider.StepCtx) {
-		resp, err := hlfProxy.Query(channel, "allowedBalanceOf", userAddressBase58Check, tokenUppercase)
-		sCtx.Require().NoError(err)
-		sCtx.Require().Equal("\""+amount+"\"", string(resp.Payload))
+		CheckAllowedBalanceEqualInStep(sCtx, hlfProxy, userAddressBase58Check, channel, tokenUppercase, amount)
 	})
 }
 
+// CheckAllowedBalanceEqualInStep checks in current allure step that allowed balance of userAddressBase58Check is equal to amount
+func CheckAllowedBalanceEqualInStep(
+	sCtx provider.StepCtx,
+	hlfProxy ChaincodeClient,
+	userAddressBase58Check string,
+	channel string,
+	tokenUppercase string,
+	amount string,
+) {
+	balance, err := GetAllowedBalance(ClientInStep(hlfProxy, sCtx), userAddressBase58Check, channel, tokenUppercase)
+	sCtx.Require().NoError(err)
+	sCtx.Require().Equal(amount, balance.String())
+}
+
 // CheckBalanceEqualWithRetry checks that balance of userAddressBase58Check is equal to amount with retries
-func CheckBalanceEqualWithRetry(t provider.T, hlfProxy HlfProxyService, userAddressBase58Check string, channel string, amount string, sleep time.Duration, retries int) {
+func CheckBalanceEqualWithRetry(t provider.T, hlfProxy ChaincodeClient, userAddressBase58Check string, channel string, amount string, sleep time.Duration, retries int) {
 	t.WithNewStep("Checking that balance equal "+amount+" with retry", func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
 		i := 0
 		for i < retries {
-			respGetBalance, err := hlfProxy.Query(channel, "balanceOf", userAddressBase58Check)
+			respGetBalance, err := hlfProxy.QueryContext(context.Background(), channel, "balanceOf", userAddressBase58Check)
 			t.Require().NoError(err)
 			if string(respGetBalance.Payload) == "\""+amount+"\"" {
 				return
@@ -96,28 +151,17 @@ func CheckBalanceEqualWithRetry(t provider.T, hlfProxy HlfProxyService, userAddr
 // TransferCheckBalanceAndGetRespose transfers amount of tokens from userFrom to userToAddress and checks that balance of userToAddress is equal to amount
 func TransferCheckBalanceAndGetRespose(
 	t provider.T,
-	hlfProxy HlfProxyService,
+	hlfProxy ChaincodeClient,
 	userFrom User,
 	userToAddress string,
 	channel string,
 	chaincode string,
 	amount string,
 ) *Response {
-	var (
-		signedTransferArgs []string
-		err                error
-		resTransfer        *Response
-	)
-
-	t.WithNewStep("Sign arguments before emission process", func(sCtx provider.StepCtx) {
-		ref := "ref transfer"
-		transferArgs := []string{userToAddress, amount, ref}
-		signedTransferArgs, err = Sign(userFrom.UserEd25519PrivateKey, userFrom.UserEd25519PublicKey, channel, chaincode, "transfer", transferArgs)
-		sCtx.Require().NoError(err)
-	})
-
-	t.WithNewStep("Invoke fiat chaincode by issuer for token emission", func(sCtx provider.StepCtx) {
-		resTransfer, err = hlfProxy.Invoke(channel, "transfer", signedTransferArgs...)
+	var resTransfer *Response
+	t.WithNewStep("Transfer "+amount+" token to user "+userToAddress, func(sCtx provider.StepCtx) {
+		var err error
+		resTransfer, err = Transfer(ClientInStep(hlfProxy, sCtx), userFrom, userToAddress, channel, chaincode, amount, "ref transfer")
 		sCtx.Require().NoError(err)
 		time.Sleep(BatchTransactionTimeout)
 	})
@@ -126,59 +170,127 @@ func TransferCheckBalanceAndGetRespose(
 	return resTransfer
 }
 
-// GetEmitPayload emits amount of tokens to userAddressBase58Check and checks that balance is equal to amount
+// Transfer transfers amount of tokens from userFrom to userToAddress with reference ref
+func Transfer(
+	hlfProxy ChaincodeClient,
+	userFrom User,
+	userToAddress string,
+	channel string,
+	chaincode string,
+	amount string,
+	ref string,
+) (*Response, error) {
+	return InvokeByUser(hlfProxy, userFrom, channel, chaincode, "transfer", userToAddress, amount, ref)
+}
+
+// SwapBegin starts swap of amount of tokens of user from channel chFrom to channel chTo with DefaultSwapHash
+func SwapBegin(hlfProxy ChaincodeClient, user User, chFrom Channel, chTo Channel, amount string) (*Response, error) {
+	return InvokeByUser(hlfProxy, user, chFrom.Name, chFrom.Chaincode, "swapBegin", chFrom.Ticker, chTo.Ticker, amount, DefaultSwapHash)
+}
+
+// SwapDone finishes swap started by transaction swapBeginTxID in channel chTo with DefaultSwapKey
+func SwapDone(hlfProxy ChaincodeClient, chTo Channel, swapBeginTxID string) (*Response, error) {
+	return hlfProxy.InvokeContext(context.Background(), chTo.Name, "swapDone", swapBeginTxID, DefaultSwapKey)
+}
+
+// GetEmitPayload emits amount of tokens to userAddressBase58Check in fiat channel of stand selected by env
+// with arguments signed for inv channel and checks that balance is equal to amount
 func GetEmitPayload(
 	t provider.T,
-	hlfProxy HlfProxyService,
+	hlfProxy ChaincodeClient,
+	userAddressBase58Check string,
+	issuer Issuer,
+	amount string,
+) string {
+	return GetEmitPayloadInNetwork(t, hlfProxy, standNetwork(t), userAddressBase58Check, issuer, amount)
+}
+
+// GetEmitPayloadInNetwork emits amount of tokens to userAddressBase58Check in fiat channel of network
+// with arguments signed for inv channel and checks that balance is equal to amount
+func GetEmitPayloadInNetwork(
+	t provider.T,
+	hlfProxy ChaincodeClient,
+	network Network,
 	userAddressBase58Check string,
 	issuer Issuer,
 	amount string,
 ) string {
 	var txID string
+	inv := network.Channel(ChannelInv)
+	fiat := network.Channel(ChannelFiat)
 	t.WithNewStep("Emit "+amount+" token to user "+userAddressBase58Check+" and get txId", func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
 		emitArgs := []string{userAddressBase58Check, amount}
-		signedEmitArgs, err := Sign(issuer.IssuerEd25519PrivateKey, issuer.IssuerEd25519PublicKey, "inv", "inv", "emit", emitArgs)
+		signedEmitArgs, err := Sign(issuer.IssuerEd25519PrivateKey, issuer.IssuerEd25519PublicKey, inv.Name, inv.Chaincode, "emit", emitArgs)
 		sCtx.Require().NoError(err)
-		res, err := hlfProxy.Invoke("fiat", "emit", signedEmitArgs...)
+		res, err := hlfProxy.InvokeContext(context.Background(), fiat.Name, "emit", signedEmitArgs...)
 		sCtx.Require().NoError(err)
 		time.Sleep(BatchTransactionTimeout)
 		txID = res.TransactionID
 	})
 
-	CheckBalanceEqual(t, hlfProxy, userAddressBase58Check, "fiat", amount)
+	CheckBalanceEqual(t, hlfProxy, userAddressBase58Check, fiat.Name, amount)
 	return txID
 }
 
-// SwapFiatToCCCheckBalanceAndGetSwapDoneAndSwapBeginTxID swaps amount of tokens from fiat to cc channel
-func SwapFiatToCCCheckBalanceAndGetSwapDoneAndSwapBeginTxID(t provider.T, hlfProxy HlfProxyService, user User, amount string) (string, string) {
+// SwapFiatToCCCheckBalanceAndGetSwapDoneAndSwapBeginTxID swaps amount of tokens from fiat to cc channel of stand selected by env
+// and checks that allowed balance of user in cc channel is equal to 1 as it always did
+func SwapFiatToCCCheckBalanceAndGetSwapDoneAndSwapBeginTxID(t provider.T, hlfProxy ChaincodeClient, user User, amount string) (string, string) {
+	return swapCheckBalanceAndGetTxIDs(t, hlfProxy, standNetwork(t), user, ChannelFiat, ChannelCC, amount, "1")
+}
+
+// SwapCheckBalanceAndGetSwapDoneAndSwapBeginTxID swaps amount of tokens between channels of network registered by keys from and to
+// and checks that allowed balance of user in channel to is equal to amount
+func SwapCheckBalanceAndGetSwapDoneAndSwapBeginTxID(
+	t provider.T,
+	hlfProxy ChaincodeClient,
+	network Network,
+	user User,
+	from string,
+	to string,
+	amount string,
+) (string, string) {
+	return swapCheckBalanceAndGetTxIDs(t, hlfProxy, network, user, from, to, amount, amount)
+}
+
+// swapCheckBalanceAndGetTxIDs swaps amount of tokens between channels from and to
+// and checks that allowed balance of user in channel to is equal to expectedAllowed
+func swapCheckBalanceAndGetTxIDs(
+	t provider.T,
+	hlfProxy ChaincodeClient,
+	network Network,
+	user User,
+	from string,
+	to string,
+	amount string,
+	expectedAllowed string,
+) (string, string) {
 	var (
 		swapBeginTxID string
 		swapDoneTxID  string
 	)
+	chFrom := network.Channel(from)
+	chTo := network.Channel(to)
 	t.WithNewStep("Swap between channels", func(sCtx provider.StepCtx) {
-		swapBeginArgs := []string{"FIAT", "CC", amount, DefaultSwapHash}
-		signedSwapBeginArgs, err := Sign(user.UserEd25519PrivateKey, user.UserEd25519PublicKey, "fiat", "fiat", "swapBegin", swapBeginArgs)
-		sCtx.Assert().NoError(err)
-		swapBeginResp, err := hlfProxy.Invoke("fiat", "swapBegin", signedSwapBeginArgs...)
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		swapBeginResp, err := SwapBegin(hlfProxy, user, chFrom, chTo, amount)
+		sCtx.Require().NoError(err)
 		swapBeginTxID = swapBeginResp.TransactionID
-		sCtx.Assert().NoError(err)
 		time.Sleep(BatchTransactionTimeout)
 
-		sCtx.NewStep("swapGet txID in fiat channel")
-		_, err = hlfProxy.Query("fiat", "swapGet", swapBeginResp.TransactionID)
-		sCtx.Assert().NoError(err)
-		sCtx.NewStep("swapGet txID in cc channel")
-		_, err = hlfProxy.Query("cc", "swapGet", swapBeginResp.TransactionID)
-		sCtx.Assert().NoError(err)
+		sCtx.NewStep("swapGet txID in " + chFrom.Name + " channel")
+		_, err = hlfProxy.QueryContext(context.Background(), chFrom.Name, "swapGet", swapBeginResp.TransactionID)
+		sCtx.Require().NoError(err)
+		sCtx.NewStep("swapGet txID in " + chTo.Name + " channel")
+		_, err = hlfProxy.QueryContext(context.Background(), chTo.Name, "swapGet", swapBeginResp.TransactionID)
+		sCtx.Require().NoError(err)
 		sCtx.NewStep("swapDone")
-		swapDoneResp, err := hlfProxy.Invoke("cc", "swapDone", swapBeginResp.TransactionID, DefaultSwapKey)
+		swapDoneResp, err := SwapDone(hlfProxy, chTo, swapBeginResp.TransactionID)
+		sCtx.Require().NoError(err)
 		swapDoneTxID = swapDoneResp.TransactionID
-		sCtx.Assert().NoError(err)
 		time.Sleep(BatchTransactionTimeout)
-		sCtx.NewStep("Get allowed balance in cc channel")
-		respAllowedBalance, err := hlfProxy.Query("cc", "allowedBalanceOf", user.UserAddressBase58Check, "FIAT")
-		sCtx.Assert().NoError(err)
-		sCtx.Require().Equal("\"1\"", string(respAllowedBalance.Payload))
+		sCtx.NewStep("Get allowed balance in " + chTo.Name + " channel")
+		CheckAllowedBalanceEqualInStep(sCtx, hlfProxy, user.UserAddressBase58Check, chTo.Name, chFrom.Ticker, expectedAllowed)
 	})
 	return swapBeginTxID, swapDoneTxID
 }
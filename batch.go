@@ -0,0 +1,246 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+const (
+	// BatchResultFcn - name of chaincode query returning result of transaction executed in batch by its id,
+	// it depends on chaincode, foundation has no such method, see NewChaincodeBatchResultSourceFromEnv
+	BatchResultFcn = "BATCH_RESULT_FCN"
+	// BatchResultNotFoundMessage - part of error message of BatchResultFcn query while transaction is not executed in batch yet
+	BatchResultNotFoundMessage = "BATCH_RESULT_NOT_FOUND_MESSAGE"
+	// ObserverBatchTxPath - path of observer api returning result of transaction executed in batch, tx id is appended to path.
+	// It depends on observer deployment, see NewObserverBatchResultSourceFromEnv
+	ObserverBatchTxPath = "OBSERVER_BATCH_TX_PATH"
+	// BatchResultTimeout - time to wait until transaction is executed in batch by robot
+	BatchResultTimeout = 30 * time.Second
+	// BatchResultPollInterval - interval between requests for result of transaction executed in batch
+	BatchResultPollInterval = 500 * time.Millisecond
+)
+
+// ErrBatchResultNotFound - transaction is not executed in batch yet
+var ErrBatchResultNotFound = errors.New("batch result not found")
+
+// BatchTxResult struct for result of transaction executed in batch
+type BatchTxResult struct {
+	TxID   string        `json:"id"`
+	Method string        `json:"method,omitempty"`
+	Error  *BatchTxError `json:"error,omitempty"`
+}
+
+// BatchTxError struct for error of transaction executed in batch
+type BatchTxError struct {
+	Code  int32  `json:"code"`
+	Error string `json:"error"`
+}
+
+// BatchResultSource - source of results of transactions executed in batch.
+// GetBatchResult returns ErrBatchResultNotFound if transaction is not executed yet
+type BatchResultSource interface {
+	GetBatchResult(ctx context.Context, channel string, txID string) (*BatchTxResult, error)
+}
+
+// ChaincodeBatchResultSource gets batch results by chaincode query
+// fcn - name of chaincode query taking transaction id
+// notFoundMessage - part of error message chaincode returns while transaction is not executed in batch yet
+type ChaincodeBatchResultSource struct {
+	hlfProxy        ChaincodeClient
+	fcn             string
+	notFoundMessage string
+}
+
+// NewChaincodeBatchResultSource - create source querying chaincode method fcn, errors of query containing notFoundMessage
+// mean that transaction is not executed yet. Both depend on chaincode and have no defaults
+func NewChaincodeBatchResultSource(hlfProxy ChaincodeClient, fcn string, notFoundMessage string) *ChaincodeBatchResultSource {
+	return &ChaincodeBatchResultSource{
+		hlfProxy:        hlfProxy,
+		fcn:             fcn,
+		notFoundMessage: notFoundMessage,
+	}
+}
+
+// NewChaincodeBatchResultSourceFromEnv - create source querying chaincode method from BatchResultFcn env
+// with not found message from BatchResultNotFoundMessage env, returns error if any of them is not set
+func NewChaincodeBatchResultSourceFromEnv(hlfProxy ChaincodeClient) (*ChaincodeBatchResultSource, error) {
+	fcn, err := requiredEnv(BatchResultFcn)
+	if err != nil {
+		return nil, err
+	}
+	notFoundMessage, err := requiredEnv(BatchResultNotFoundMessage)
+	if err != nil {
+		return nil, err
+	}
+	return NewChaincodeBatchResultSource(hlfProxy, fcn, notFoundMessage), nil
+}
+
+// GetBatchResult - query result of transaction executed in batch from chaincode.
+// Only not found error of chaincode is returned as ErrBatchResultNotFound, other errors are returned as is
+func (s *ChaincodeBatchResultSource) GetBatchResult(ctx context.Context, channel string, txID string) (*BatchTxResult, error) {
+	resp, err := s.hlfProxy.QueryContext(ctx, channel, s.fcn, txID)
+	if err != nil {
+		if strings.Contains(err.Error(), s.notFoundMessage) {
+			return nil, fmt.Errorf("%w: %v", ErrBatchResultNotFound, err)
+		}
+		return nil, err
+	}
+
+	result := &BatchTxResult{}
+	if err = json.Unmarshal(resp.Payload, result); err != nil {
+		return nil, fmt.Errorf("json unmarshal batch result: %w", err)
+	}
+
+	return result, nil
+}
+
+// ObserverBatchResultSource gets batch results from observer service
+// url - observer api url, example http://localhost:3335/api without '/' on the end the string
+// path - path of api returning transaction by id
+type ObserverBatchResultSource struct {
+	url  string
+	path string
+}
+
+// NewObserverBatchResultSource - create source requesting observer api url with txPath, tx id is appended to txPath.
+// Path depends on observer deployment and has no default, see Stand.NewObserverBatchResultSource
+func NewObserverBatchResultSource(observerAPIURL string, txPath string) *ObserverBatchResultSource {
+	return &ObserverBatchResultSource{
+		url:  observerAPIURL,
+		path: txPath,
+	}
+}
+
+// NewObserverBatchResultSourceFromEnv - create source requesting observer api url with path from ObserverBatchTxPath env,
+// returns error if it is not set
+func NewObserverBatchResultSourceFromEnv(observerAPIURL string) (*ObserverBatchResultSource, error) {
+	txPath, err := requiredEnv(ObserverBatchTxPath)
+	if err != nil {
+		return nil, err
+	}
+	return NewObserverBatchResultSource(observerAPIURL, txPath), nil
+}
+
+// GetBatchResult - request result of transaction executed in batch from observer
+func (s *ObserverBatchResultSource) GetBatchResult(ctx context.Context, channel string, txID string) (*BatchTxResult, error) {
+	u, err := url.Parse(s.url)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, s.path, txID)
+	q := u.Query()
+	q.Set("channel", channel)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, ErrBatchResultNotFound
+	default:
+		return nil, fmt.Errorf("observer status code %d: %s", resp.StatusCode, body)
+	}
+
+	result := &BatchTxResult{}
+	if err = json.Unmarshal(body, result); err != nil {
+		return nil, fmt.Errorf("json unmarshal batch result: %w", err)
+	}
+
+	return result, nil
+}
+
+// WaitBatchResult waits until transaction is executed in batch and returns its result
+func WaitBatchResult(ctx context.Context, source BatchResultSource, channel string, txID string) (*BatchTxResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, BatchResultTimeout)
+	defer cancel()
+
+	for {
+		result, err := source.GetBatchResult(ctx, channel, txID)
+		if !errors.Is(err, ErrBatchResultNotFound) {
+			return result, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait batch result of tx %s: %w", txID, err)
+		case <-time.After(BatchResultPollInterval):
+		}
+	}
+}
+
+// CheckBatchTxSuccess checks that transaction is executed in batch without error
+func CheckBatchTxSuccess(t provider.T, source BatchResultSource, channel string, txID string) {
+	t.WithNewStep("Checking that tx "+txID+" is executed in batch without error", func(sCtx provider.StepCtx) {
+		result := waitBatchResultInStep(sCtx, source, channel, txID)
+		sCtx.Require().Nil(result.Error, "batch tx failed: %+v", result.Error)
+	})
+}
+
+// CheckBatchTxError checks that transaction is executed in batch with error containing errorMessage
+func CheckBatchTxError(t provider.T, source BatchResultSource, channel string, txID string, errorMessage string) {
+	t.WithNewStep("Checking that tx "+txID+" is executed in batch with error "+errorMessage, func(sCtx provider.StepCtx) {
+		result := waitBatchResultInStep(sCtx, source, channel, txID)
+		sCtx.Require().NotNil(result.Error)
+		sCtx.Require().Contains(result.Error.Error, errorMessage)
+	})
+}
+
+// InvokeAndCheckBatchTxSuccess invokes chaincode and checks that transaction is executed in batch without error
+func InvokeAndCheckBatchTxSuccess(t provider.T, hlfProxy ChaincodeClient, source BatchResultSource, channel string, fcn string, args ...string) *Response {
+	var res *Response
+	t.WithNewStep("Invoke "+fcn+" in channel "+channel, func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		var err error
+		res, err = hlfProxy.InvokeContext(context.Background(), channel, fcn, args...)
+		sCtx.Require().NoError(err)
+	})
+
+	CheckBatchTxSuccess(t, source, channel, res.TransactionID)
+	return res
+}
+
+// requiredEnv returns value of env key, error if it is not set or empty
+func requiredEnv(key string) (string, error) {
+	value := GetEnv(key, "")
+	if value == "" {
+		return "", errors.New("env " + key + " is not set")
+	}
+	return value, nil
+}
+
+func waitBatchResultInStep(sCtx provider.StepCtx, source BatchResultSource, channel string, txID string) *BatchTxResult {
+	result, err := WaitBatchResult(context.Background(), source, channel, txID)
+	sCtx.Require().NoError(err)
+	if data, err := json.Marshal(result); err == nil {
+		sCtx.WithNewAttachment("batch result", allure.JSON, data)
+	}
+	return result
+}
@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// queryStub - ChaincodeClient returning payload and error of query
+type queryStub struct {
+	payload []byte
+	err     error
+}
+
+func (s queryStub) InvokeContext(context.Context, string, string, ...string) (*Response, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s queryStub) QueryContext(context.Context, string, string, ...string) (*Response, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &Response{Payload: s.payload}, nil
+}
+
+func TestChaincodeBatchResultSource(t *testing.T) {
+	tests := []struct {
+		name     string
+		client   queryStub
+		notFound bool
+		err      bool
+		result   *BatchTxResult
+	}{
+		{name: "executed", client: queryStub{payload: []byte(`{"id":"tx"}`)}, result: &BatchTxResult{TxID: "tx"}},
+		{name: "not executed yet", client: queryStub{err: errors.New("batch tx tx not found")}, notFound: true},
+		{name: "unknown method", client: queryStub{err: errors.New("method 'getResult' not allowed")}, err: true},
+		{name: "bad payload", client: queryStub{payload: []byte(`[`)}, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewChaincodeBatchResultSource(tt.client, "getResult", "not found").GetBatchResult(context.Background(), "fiat", "tx")
+			switch {
+			case tt.notFound:
+				require.ErrorIs(t, err, ErrBatchResultNotFound)
+			case tt.err:
+				require.Error(t, err)
+				require.NotErrorIs(t, err, ErrBatchResultNotFound)
+			default:
+				require.NoError(t, err)
+				require.Equal(t, tt.result, result)
+			}
+		})
+	}
+}
+
+func TestObserverBatchResultSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/batch/transaction/done":
+			_, _ = w.Write([]byte(`{"id":"done","error":{"code":500,"error":"insufficient funds"}}`))
+		case "/api/batch/transaction/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	source := NewObserverBatchResultSource(server.URL+"/api", "batch/transaction")
+	tests := []struct {
+		txID     string
+		notFound bool
+		err      bool
+	}{
+		{txID: "done"},
+		{txID: "pending", notFound: true},
+		{txID: "broken", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.txID, func(t *testing.T) {
+			result, err := source.GetBatchResult(context.Background(), "fiat", tt.txID)
+			switch {
+			case tt.notFound:
+				require.ErrorIs(t, err, ErrBatchResultNotFound)
+			case tt.err:
+				require.Error(t, err)
+				require.NotErrorIs(t, err, ErrBatchResultNotFound)
+			default:
+				require.NoError(t, err)
+				require.Equal(t, "insufficient funds", result.Error.Error)
+			}
+		})
+	}
+}
+
+func TestBatchResultSourceFromEnv(t *testing.T) {
+	chaincode := func() error {
+		source, err := NewChaincodeBatchResultSourceFromEnv(queryStub{})
+		if err == nil {
+			require.Equal(t, "getResult", source.fcn)
+			require.Equal(t, "not found", source.notFoundMessage)
+		}
+		return err
+	}
+	observer := func() error {
+		source, err := NewObserverBatchResultSourceFromEnv("http://localhost:3335/api")
+		if err == nil {
+			require.Equal(t, "batch/transaction", source.path)
+		}
+		return err
+	}
+
+	tests := []struct {
+		name      string
+		newSource func() error
+		env       map[string]string
+		err       string
+	}{
+		{name: "chaincode", newSource: chaincode, env: map[string]string{BatchResultFcn: "getResult", BatchResultNotFoundMessage: "not found"}},
+		{name: "chaincode fcn is not set", newSource: chaincode, env: map[string]string{BatchResultNotFoundMessage: "not found"}, err: BatchResultFcn},
+		{name: "chaincode not found message is not set", newSource: chaincode, env: map[string]string{BatchResultFcn: "getResult"}, err: BatchResultNotFoundMessage},
+		{name: "observer", newSource: observer, env: map[string]string{ObserverBatchTxPath: "batch/transaction"}},
+		{name: "observer path is not set", newSource: observer, err: ObserverBatchTxPath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{BatchResultFcn, BatchResultNotFoundMessage, ObserverBatchTxPath} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			err := tt.newSource()
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
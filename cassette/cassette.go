@@ -0,0 +1,108 @@
+package cassette
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	utils "github.com/anoideaopen/testnet-util"
+)
+
+const (
+	// EnvMode - mode of cassette transport: record, replay or empty for real requests only
+	EnvMode = "CASSETTE_MODE"
+	// EnvPath - path to cassette file
+	EnvPath = "CASSETTE_PATH"
+	// ModeRecord - send requests and save request/response pairs to cassette
+	ModeRecord = "record"
+	// ModeReplay - serve responses from cassette without sending requests
+	ModeReplay = "replay"
+)
+
+// Request struct for recorded http request
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response struct for recorded http response
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Interaction struct for recorded request/response pair
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette struct for recorded interactions in order they were made
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Load - load cassette from json file
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read cassette: %w", err)
+	}
+
+	c := &Cassette{}
+	if err = json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("json unmarshal cassette: %w", err)
+	}
+
+	return c, nil
+}
+
+// Save - write cassette to json file
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("json marshal cassette: %w", err)
+	}
+
+	if err = os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write cassette: %w", err)
+	}
+
+	return nil
+}
+
+// Transport - recording or replaying transport, Close saves recorded cassette
+type Transport interface {
+	http.RoundTripper
+	Close() error
+}
+
+// FromEnv returns transport by EnvMode and EnvPath env, transport sends real requests only if mode is not set
+func FromEnv() (Transport, error) {
+	path := utils.GetEnv(EnvPath, "cassette.json")
+
+	switch mode := utils.GetEnv(EnvMode, ""); mode {
+	case "":
+		return passthrough{http.DefaultTransport}, nil
+	case ModeRecord:
+		return NewRecorder(path, http.DefaultTransport), nil
+	case ModeReplay:
+		return NewPlayer(path, DefaultMatcher)
+	default:
+		return nil, errors.New("unknown cassette mode " + mode)
+	}
+}
+
+// passthrough - transport sending requests without recording
+type passthrough struct {
+	http.RoundTripper
+}
+
+// Close - nothing to save
+func (passthrough) Close() error {
+	return nil
+}
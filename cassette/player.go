@@ -0,0 +1,134 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+
+	"github.com/btcsuite/btcutil/base58"
+	"golang.org/x/crypto/ed25519"
+)
+
+// Matcher - returns true if request made in replay matches recorded one
+type Matcher func(recorded Request, actual Request) bool
+
+var nonceArg = regexp.MustCompile(`^\d{13}$`)
+
+// Player - transport serving recorded responses in order of recording, each interaction is served once
+type Player struct {
+	matcher  Matcher
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// NewPlayer - create player for cassette from path
+func NewPlayer(path string, matcher Matcher) (*Player, error) {
+	c, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Player{matcher: matcher, cassette: c, used: make([]bool, len(c.Interactions))}, nil
+}
+
+// RoundTrip - return response of first not served interaction matching request
+func (p *Player) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	actual := Request{Method: req.Method, URL: req.URL.String(), Body: string(body)}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, interaction := range p.cassette.Interactions {
+		if p.used[i] || !p.matcher(interaction.Request, actual) {
+			continue
+		}
+		p.used[i] = true
+		return &http.Response{
+			Status:        http.StatusText(interaction.Response.StatusCode),
+			StatusCode:    interaction.Response.StatusCode,
+			Header:        interaction.Response.Header,
+			Body:          io.NopCloser(bytes.NewReader([]byte(interaction.Response.Body))),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("cassette: no recorded interaction for %s %s %s", actual.Method, actual.URL, actual.Body)
+}
+
+// Close - nothing to save in replay
+func (p *Player) Close() error {
+	return nil
+}
+
+// DefaultMatcher matches method, url path and hlf proxy request body ignoring nonces and signatures in args.
+// Bodies which are not hlf proxy requests are compared as is
+func DefaultMatcher(recorded Request, actual Request) bool {
+	if recorded.Method != actual.Method || urlPath(recorded.URL) != urlPath(actual.URL) {
+		return false
+	}
+	return normalizeBody(recorded.Body) == normalizeBody(actual.Body)
+}
+
+func urlPath(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return u
+	}
+	return parsed.Path
+}
+
+// proxyRequest - hlf proxy request with args decoded
+type proxyRequest struct {
+	Args        [][]byte        `json:"args"`
+	ChaincodeID string          `json:"chaincodeId"`
+	Fcn         string          `json:"fcn"`
+	Options     json.RawMessage `json:"options,omitempty"`
+}
+
+func normalizeBody(body string) string {
+	req := proxyRequest{}
+	if err := json.Unmarshal([]byte(body), &req); err != nil || req.Fcn == "" {
+		return body
+	}
+
+	args := make([]string, len(req.Args))
+	for i, arg := range req.Args {
+		args[i] = normalizeArg(string(arg))
+	}
+
+	normalized, err := json.Marshal(struct {
+		Args        []string        `json:"args"`
+		ChaincodeID string          `json:"chaincodeId"`
+		Fcn         string          `json:"fcn"`
+		Options     json.RawMessage `json:"options,omitempty"`
+	}{args, req.ChaincodeID, req.Fcn, req.Options})
+	if err != nil {
+		return body
+	}
+	return string(normalized)
+}
+
+// normalizeArg replaces nonce and ed25519 signatures in base58 or hex with placeholders
+func normalizeArg(arg string) string {
+	if nonceArg.MatchString(arg) {
+		return "<nonce>"
+	}
+	if len(base58.Decode(arg)) == ed25519.SignatureSize {
+		return "<signature>"
+	}
+	if decoded, err := hex.DecodeString(arg); err == nil && len(decoded) == ed25519.SignatureSize {
+		return "<signature>"
+	}
+	return arg
+}
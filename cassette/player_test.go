@@ -0,0 +1,152 @@
+package cassette
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/require"
+)
+
+func proxyBody(t *testing.T, fcn string, args ...string) string {
+	t.Helper()
+	byteArgs := make([][]byte, len(args))
+	for i, arg := range args {
+		byteArgs[i] = []byte(arg)
+	}
+	body, err := json.Marshal(proxyRequest{Args: byteArgs, ChaincodeID: "fiat", Fcn: fcn})
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestNormalizeArg(t *testing.T) {
+	signature := make([]byte, 64)
+	signature[0] = 1
+
+	tests := []struct {
+		name     string
+		arg      string
+		expected string
+	}{
+		{name: "nonce", arg: "1700000000000", expected: "<nonce>"},
+		{name: "short number", arg: "100", expected: "100"},
+		{name: "base58 signature", arg: base58.Encode(signature), expected: "<signature>"},
+		{name: "hex signature", arg: hex.EncodeToString(signature), expected: "<signature>"},
+		{name: "short hex", arg: hex.EncodeToString(signature[:32]), expected: hex.EncodeToString(signature[:32])},
+		{name: "plain", arg: "transfer", expected: "transfer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, normalizeArg(tt.arg))
+		})
+	}
+}
+
+func TestDefaultMatcher(t *testing.T) {
+	signature := make([]byte, 64)
+	signature[0] = 1
+	otherSignature := make([]byte, 64)
+	otherSignature[0] = 2
+
+	recorded := Request{
+		Method: http.MethodPost,
+		URL:    "http://proxy:9001/invoke?channel=fiat",
+		Body:   proxyBody(t, "transfer", "to", "10", "1700000000000", base58.Encode(signature)),
+	}
+
+	tests := []struct {
+		name    string
+		actual  Request
+		matches bool
+	}{
+		{name: "same", actual: recorded, matches: true},
+		{
+			name: "other nonce and signature",
+			actual: Request{
+				Method: http.MethodPost,
+				URL:    recorded.URL,
+				Body:   proxyBody(t, "transfer", "to", "10", "1700000000001", base58.Encode(otherSignature)),
+			},
+			matches: true,
+		},
+		{
+			name:    "other host and query",
+			actual:  Request{Method: http.MethodPost, URL: "http://localhost:9001/invoke", Body: recorded.Body},
+			matches: true,
+		},
+		{
+			name:   "other amount",
+			actual: Request{Method: http.MethodPost, URL: recorded.URL, Body: proxyBody(t, "transfer", "to", "11", "1700000000000", base58.Encode(signature))},
+		},
+		{
+			name:   "other fcn",
+			actual: Request{Method: http.MethodPost, URL: recorded.URL, Body: proxyBody(t, "emit", "to", "10", "1700000000000", base58.Encode(signature))},
+		},
+		{name: "other path", actual: Request{Method: http.MethodPost, URL: "http://proxy:9001/query", Body: recorded.Body}},
+		{name: "other method", actual: Request{Method: http.MethodGet, URL: recorded.URL, Body: recorded.Body}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.matches, DefaultMatcher(recorded, tt.actual))
+		})
+	}
+}
+
+func TestDefaultMatcherNotProxyBody(t *testing.T) {
+	recorded := Request{Method: http.MethodPost, URL: "http://observer/v1/tx", Body: `{"id":"1700000000000"}`}
+
+	require.True(t, DefaultMatcher(recorded, recorded))
+	require.False(t, DefaultMatcher(recorded, Request{Method: http.MethodPost, URL: recorded.URL, Body: `{"id":"1700000000001"}`}))
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		_, _ = w.Write([]byte(r.URL.Path + ":" + string(body)))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	bodies := []string{
+		proxyBody(t, "transfer", "to", "10", "1700000000000"),
+		proxyBody(t, "transfer", "to", "10", "1700000000001"),
+	}
+
+	recorder := NewRecorder(path, http.DefaultTransport)
+	client := &http.Client{Transport: recorder}
+	for _, body := range bodies {
+		resp, err := client.Post(server.URL+"/invoke", "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+	require.NoError(t, recorder.Close())
+	require.Equal(t, 2, calls)
+
+	player, err := NewPlayer(path, DefaultMatcher)
+	require.NoError(t, err)
+	client = &http.Client{Transport: player}
+
+	// interactions are served in order of recording whatever nonces are sent in replay
+	for _, recorded := range bodies {
+		resp, err := client.Post(server.URL+"/invoke", "application/json",
+			strings.NewReader(proxyBody(t, "transfer", "to", "10", "1800000000000")))
+		require.NoError(t, err)
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, "/invoke:"+recorded, string(data))
+	}
+	require.Equal(t, 2, calls, "replay doesn't send requests")
+
+	_, err = client.Post(server.URL+"/invoke", "application/json", strings.NewReader(bodies[0]))
+	require.Error(t, err, "every interaction is served once")
+}
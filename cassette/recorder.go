@@ -0,0 +1,71 @@
+package cassette
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Recorder - transport sending requests through next transport and recording request/response pairs
+type Recorder struct {
+	next     http.RoundTripper
+	path     string
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder - create recorder saving cassette to path on Close
+func NewRecorder(path string, next http.RoundTripper) *Recorder {
+	return &Recorder{next: next, path: path}
+}
+
+// RoundTrip - send request and record it with response
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request:  Request{Method: req.Method, URL: req.URL.String(), Body: string(reqBody)},
+		Response: Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: string(respBody)},
+	})
+
+	return resp, nil
+}
+
+// Close - save recorded cassette
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cassette.Save(r.path)
+}
+
+// readBody reads body and replaces it with reader over read bytes
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	if err != nil {
+		return nil, err
+	}
+	if err = (*body).Close(); err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
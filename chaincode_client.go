@@ -0,0 +1,13 @@
+package utils
+
+import "context"
+
+// ChaincodeClient - backend helpers send chaincode requests through, implemented by HlfProxyService
+// and by fakes from package fake, so suites can swap backends without changing tests.
+// InvokeContext returns response together with ErrTxNotValid if transaction was not committed as VALID
+type ChaincodeClient interface {
+	InvokeContext(ctx context.Context, chaincodeID string, fcn string, args ...string) (*Response, error)
+	QueryContext(ctx context.Context, chaincodeID string, fcn string, args ...string) (*Response, error)
+}
+
+var _ ChaincodeClient = (*HlfProxyService)(nil)
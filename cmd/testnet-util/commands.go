@@ -0,0 +1,358 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	utils "github.com/anoideaopen/testnet-util"
+	"golang.org/x/crypto/ed25519"
+)
+
+// identity - output of commands creating or decoding keys
+type identity struct {
+	PrivateKey string `json:"privateKey,omitempty"`
+	PublicKey  string `json:"publicKey"`
+	Address    string `json:"address"`
+}
+
+// response - output of commands sending requests to hlf proxy, payload is kept as json if it is valid json
+type response struct {
+	TransactionID    string          `json:"transactionId,omitempty"`
+	BlockNumber      int64           `json:"blockNumber,omitempty"`
+	ValidationCode   string          `json:"validationCode,omitempty"`
+	ChaincodeStatus  int64           `json:"chaincodeStatus,omitempty"`
+	Payload          json.RawMessage `json:"payload,omitempty"`
+	PayloadRaw       string          `json:"payloadRaw,omitempty"`
+	TxValidationCode int64           `json:"txValidationCode,omitempty"`
+}
+
+func newResponse(resp *utils.Response) response {
+	res := response{
+		TransactionID:    resp.TransactionID,
+		BlockNumber:      resp.BlockNumber,
+		ChaincodeStatus:  resp.ChaincodeStatus,
+		TxValidationCode: resp.TxValidationCode,
+	}
+	if resp.TransactionID != "" {
+		res.ValidationCode = resp.ValidationCode().String()
+	}
+	if json.Valid(resp.Payload) {
+		res.Payload = resp.Payload
+	} else if len(resp.Payload) != 0 {
+		res.PayloadRaw = string(resp.Payload)
+	}
+	return res
+}
+
+func newIdentity(privateKey ed25519.PrivateKey) (identity, error) {
+	user, err := utils.NewUser(privateKey)
+	if err != nil {
+		return identity{}, err
+	}
+	encoded, err := utils.ConvertPrivateKeyToBase58Check(privateKey)
+	if err != nil {
+		return identity{}, err
+	}
+	return identity{encoded, user.UserPublicKeyBase58, user.UserAddressBase58Check}, nil
+}
+
+func keygen(_ utils.Stand, args []string) (any, error) {
+	fs := newFlagSet("keygen")
+	seed := fs.String("seed", "", "master seed, keys are random if empty")
+	name := fs.String("name", "", "name of identity derived from seed")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	var (
+		privateKey ed25519.PrivateKey
+		err        error
+	)
+	if *seed != "" {
+		privateKey, _, err = utils.DerivePrivateAndPublicKey([]byte(*seed), *name)
+	} else {
+		privateKey, _, err = utils.GeneratePrivateAndPublicKey()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newIdentity(privateKey)
+}
+
+func address(_ utils.Stand, args []string) (any, error) {
+	fs := newFlagSet("address")
+	publicKey := fs.String("public-key", "", "public key in base58")
+	privateKey := fs.String("private-key", "", "private key in base58 check")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case *privateKey != "":
+		key, _, err := utils.GetPrivateKeyFromBase58Check(*privateKey)
+		if err != nil {
+			return nil, err
+		}
+		res, err := newIdentity(key)
+		res.PrivateKey = ""
+		return res, err
+	case *publicKey != "":
+		addr, err := utils.GetAddressByPublicKeyBase58(*publicKey)
+		return identity{PublicKey: *publicKey, Address: addr}, err
+	default:
+		return nil, errors.New("flag -public-key or -private-key is required")
+	}
+}
+
+func sign(stand utils.Stand, args []string) (any, error) {
+	fs := newFlagSet("sign")
+	privateKey := fs.String("private-key", "", "signer private key in base58 check")
+	channel := fs.String("channel", "", "registry key of channel")
+	method := fs.String("method", "", "chaincode method")
+	nonce := fs.String("nonce", "", "nonce, current time if empty")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if err := requireFlags(fs, "private-key", "channel", "method"); err != nil {
+		return nil, err
+	}
+
+	priv, pub, err := utils.GetPrivateKeyFromBase58Check(*privateKey)
+	if err != nil {
+		return nil, err
+	}
+	ch := stand.Channel(*channel)
+	return utils.SignWithNonce(priv, pub, ch.Name, ch.Chaincode, *method, fs.Args(), *nonce)
+}
+
+func acl(stand utils.Stand, args []string) (any, error) {
+	if len(args) == 0 || args[0] != "add-user" {
+		return nil, errors.New("unknown acl command, expected add-user")
+	}
+
+	fs := newFlagSet("acl add-user")
+	publicKey := fs.String("public-key", "", "public key in base58, new keys are generated if empty")
+	if err := fs.Parse(args[1:]); err != nil {
+		return nil, err
+	}
+
+	var (
+		id  identity
+		err error
+	)
+	if *publicKey == "" {
+		privateKey, _, err := utils.GeneratePrivateAndPublicKey()
+		if err != nil {
+			return nil, err
+		}
+		if id, err = newIdentity(privateKey); err != nil {
+			return nil, err
+		}
+	} else {
+		id.PublicKey = *publicKey
+		if id.Address, err = utils.GetAddressByPublicKeyBase58(*publicKey); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := newHlfProxyService(stand).Invoke(stand.Channel(utils.ChannelACL).Name, "addUser", id.PublicKey, "test", "testuser", "true")
+	if err != nil {
+		return nil, err
+	}
+
+	return struct {
+		identity
+		Response response `json:"response"`
+	}{id, newResponse(resp)}, nil
+}
+
+func emit(stand utils.Stand, args []string) (any, error) {
+	fs := newFlagSet("emit")
+	channel := fs.String("channel", utils.ChannelFiat, "registry key of channel")
+	to := fs.String("to", "", "receiver address")
+	amount := fs.String("amount", "", "amount of tokens")
+	issuerKey := fs.String("issuer-key", "", "issuer private key in base58 check, issuer of channel if empty")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if err := requireFlags(fs, "to", "amount"); err != nil {
+		return nil, err
+	}
+
+	var (
+		issuer utils.Issuer
+		err    error
+	)
+	if *issuerKey != "" {
+		issuer, err = utils.NewIssuer(*issuerKey)
+	} else {
+		issuer, err = stand.Issuer(*channel)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ch := stand.Channel(*channel)
+	resp, err := utils.Emit(newHlfProxyService(stand), *to, issuer, ch.Name, ch.Chaincode, *amount)
+	if err != nil {
+		return nil, err
+	}
+	return newResponse(resp), nil
+}
+
+func transfer(stand utils.Stand, args []string) (any, error) {
+	fs := newFlagSet("transfer")
+	channel := fs.String("channel", utils.ChannelFiat, "registry key of channel")
+	privateKey := fs.String("private-key", "", "sender private key in base58 check")
+	to := fs.String("to", "", "receiver address")
+	amount := fs.String("amount", "", "amount of tokens")
+	ref := fs.String("ref", "ref transfer", "transfer reference")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if err := requireFlags(fs, "private-key", "to", "amount"); err != nil {
+		return nil, err
+	}
+
+	return invokeSigned(stand, *privateKey, stand.Channel(*channel), "transfer", *to, *amount, *ref)
+}
+
+func balance(stand utils.Stand, args []string) (any, error) {
+	fs := newFlagSet("balance")
+	channel := fs.String("channel", utils.ChannelFiat, "registry key of channel")
+	addr := fs.String("address", "", "address")
+	token := fs.String("token", "", "ticker of token for allowed balance, token balance if empty")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if err := requireFlags(fs, "address"); err != nil {
+		return nil, err
+	}
+
+	hlfProxy := newHlfProxyService(stand)
+	ch := stand.Channel(*channel)
+
+	var (
+		resp *utils.Response
+		err  error
+	)
+	if *token != "" {
+		resp, err = hlfProxy.Query(ch.Name, "allowedBalanceOf", *addr, *token)
+	} else {
+		resp, err = hlfProxy.Query(ch.Name, "balanceOf", *addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newResponse(resp), nil
+}
+
+func swap(stand utils.Stand, args []string) (any, error) {
+	fs := newFlagSet("swap")
+	from := fs.String("from", utils.ChannelFiat, "registry key of channel swap from")
+	to := fs.String("to", utils.ChannelCC, "registry key of channel swap to")
+	privateKey := fs.String("private-key", "", "user private key in base58 check")
+	amount := fs.String("amount", "", "amount of tokens")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if err := requireFlags(fs, "private-key", "amount"); err != nil {
+		return nil, err
+	}
+
+	chFrom := stand.Channel(*from)
+	chTo := stand.Channel(*to)
+	begin, err := invokeSigned(stand, *privateKey, chFrom, "swapBegin", chFrom.Ticker, chTo.Ticker, *amount, utils.DefaultSwapHash)
+	if err != nil {
+		return nil, err
+	}
+
+	time.Sleep(utils.BatchTransactionTimeout)
+
+	done, err := newHlfProxyService(stand).Invoke(chTo.Name, "swapDone", begin.TransactionID, utils.DefaultSwapKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return struct {
+		SwapBegin response `json:"swapBegin"`
+		SwapDone  response `json:"swapDone"`
+	}{begin, newResponse(done)}, nil
+}
+
+func channelTransfer(stand utils.Stand, args []string) (any, error) {
+	fs := newFlagSet("channel-transfer")
+	from := fs.String("from", utils.ChannelFiat, "registry key of channel transfer from")
+	to := fs.String("to", utils.ChannelCC, "registry key of channel transfer to")
+	privateKey := fs.String("private-key", "", "user private key in base58 check")
+	amount := fs.String("amount", "", "amount of tokens")
+	id := fs.String("id", "", "transfer id, current time if empty")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if err := requireFlags(fs, "private-key", "amount"); err != nil {
+		return nil, err
+	}
+	if *id == "" {
+		*id = utils.GetNonce()
+	}
+
+	chFrom := stand.Channel(*from)
+	chTo := stand.Channel(*to)
+	return invokeSigned(stand, *privateKey, chFrom, "channelTransferByCustomer", *id, chTo.Ticker, chFrom.Ticker, *amount)
+}
+
+func invoke(stand utils.Stand, args []string) (any, error) {
+	return request(stand, "invoke", args)
+}
+
+func query(stand utils.Stand, args []string) (any, error) {
+	return request(stand, "query", args)
+}
+
+func request(stand utils.Stand, requestType string, args []string) (any, error) {
+	fs := newFlagSet(requestType)
+	channel := fs.String("channel", "", "channel name")
+	fcn := fs.String("fcn", "", "chaincode method")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if err := requireFlags(fs, "channel", "fcn"); err != nil {
+		return nil, err
+	}
+
+	hlfProxy := newHlfProxyService(stand)
+	send := hlfProxy.Query
+	if requestType == "invoke" {
+		send = hlfProxy.Invoke
+	}
+
+	resp, err := send(*channel, *fcn, fs.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	return newResponse(resp), nil
+}
+
+func invokeSigned(stand utils.Stand, privateKey string, ch utils.Channel, fcn string, args ...string) (response, error) {
+	priv, pub, err := utils.GetPrivateKeyFromBase58Check(privateKey)
+	if err != nil {
+		return response{}, err
+	}
+	signed, err := utils.Sign(priv, pub, ch.Name, ch.Chaincode, fcn, args)
+	if err != nil {
+		return response{}, err
+	}
+	resp, err := newHlfProxyService(stand).Invoke(ch.Name, fcn, signed...)
+	if err != nil {
+		return response{}, err
+	}
+	return newResponse(resp), nil
+}
+
+// newHlfProxyService - create hlf proxy client of stand logging to logger of cli
+func newHlfProxyService(stand utils.Stand) *utils.HlfProxyService {
+	return stand.NewHlfProxyService(utils.WithLogger(logger))
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	utils "github.com/anoideaopen/testnet-util"
+)
+
+// command - subcommand of cli, args are arguments after name of subcommand
+type command struct {
+	usage string
+	run   func(stand utils.Stand, args []string) (any, error)
+}
+
+var commands = map[string]command{
+	"keygen":           {"keygen [-seed seed -name name]", keygen},
+	"address":          {"address -public-key base58 | -private-key base58check", address},
+	"sign":             {"sign -private-key base58check -channel key -method fcn [-nonce nonce] args...", sign},
+	"acl":              {"acl add-user [-public-key base58]", acl},
+	"emit":             {"emit -channel key -to address -amount amount [-issuer-key base58check]", emit},
+	"transfer":         {"transfer -channel key -private-key base58check -to address -amount amount [-ref ref]", transfer},
+	"balance":          {"balance -channel key -address address [-token ticker]", balance},
+	"swap":             {"swap -from key -to key -private-key base58check -amount amount", swap},
+	"channel-transfer": {"channel-transfer -from key -to key -private-key base58check -amount amount [-id id]", channelTransfer},
+	"invoke":           {"invoke -channel name -fcn fcn args...", invoke},
+	"query":            {"query -channel name -fcn fcn args...", query},
+}
+
+// logger - logger of hlf proxy requests, it never writes to output of commands, see run
+var logger utils.Logger = utils.NewTextLogger(os.Stderr, utils.ParseLogLevel(utils.GetEnv(utils.LogLevelEnv, "info")))
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// run runs command writing json result to out and logs to logOut, so out is kept for json only
+func run(args []string, out io.Writer, logOut io.Writer) error {
+	logger = utils.NewTextLogger(logOut, utils.ParseLogLevel(utils.GetEnv(utils.LogLevelEnv, "info")))
+	utils.SetDefaultLogger(logger)
+
+	if len(args) == 0 {
+		return errors.New(usage())
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %s\n%s", args[0], usage())
+	}
+
+	stand, err := utils.GetStand()
+	if err != nil {
+		return err
+	}
+
+	res, err := cmd.run(stand, args[1:])
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(res)
+}
+
+func usage() string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := []string{"usage: testnet-util <command> [flags]", "stand is configured by env " +
+		utils.StandProfile + ", " + utils.HlfProxyURL + ", " + utils.HlfProxyAuthToken + ", commands:"}
+	for _, name := range names {
+		lines = append(lines, "  "+commands[name].usage)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// newFlagSet returns flag set failing with error instead of exit
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// requireFlags returns error if any of flags is empty
+func requireFlags(fs *flag.FlagSet, names ...string) error {
+	for _, name := range names {
+		if f := fs.Lookup(name); f == nil || f.Value.String() == "" {
+			return fmt.Errorf("flag -%s is required", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	utils "github.com/anoideaopen/testnet-util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunWritesOnlyJSONToOutput(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"transactionId":"tx","blockNumber":1,"payload":"IjEwIg=="}`))
+	}))
+	defer proxy.Close()
+
+	t.Setenv(utils.StandProfile, utils.DefaultStandProfile)
+	t.Setenv(utils.HlfProxyURL, proxy.URL)
+	t.Setenv(utils.LogLevelEnv, "debug")
+
+	tests := []struct {
+		name string
+		args []string
+		logs bool
+	}{
+		{name: "keygen", args: []string{"keygen", "-seed", "seed", "-name", "user"}},
+		{name: "query", args: []string{"query", "-channel", "fiat", "-fcn", "balanceOf", "address"}, logs: true},
+		{name: "invoke", args: []string{"invoke", "-channel", "fiat", "-fcn", "emit", "address", "10"}, logs: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			logOut := &bytes.Buffer{}
+			require.NoError(t, run(tt.args, out, logOut))
+
+			var res map[string]any
+			require.NoError(t, json.Unmarshal(out.Bytes(), &res), "output: %s", out)
+			if tt.logs {
+				require.Contains(t, logOut.String(), "hlf proxy request")
+			}
+		})
+	}
+}
+
+func TestRunUnknownCommand(t *testing.T) {
+	out := &bytes.Buffer{}
+	require.Error(t, run([]string{"unknown"}, out, &bytes.Buffer{}))
+	require.Error(t, run(nil, out, &bytes.Buffer{}))
+	require.Empty(t, out.String())
+}
@@ -0,0 +1,183 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/sha3"
+)
+
+// addressPayloadLen - length of address payload without version byte, address is sha3 256 of public key
+const addressPayloadLen = 31
+
+var (
+	// ErrAddressChecksum - address checksum does not match
+	ErrAddressChecksum = errors.New("invalid address checksum")
+	// ErrAddressFormat - address is not base58 check string
+	ErrAddressFormat = errors.New("invalid address format")
+	// ErrAddressLength - decoded address has wrong length
+	ErrAddressLength = errors.New("invalid address length")
+	// ErrAddressVersion - version byte of address is not expected one
+	ErrAddressVersion = errors.New("invalid address version")
+	// ErrAddressMismatch - address is not derived from public key
+	ErrAddressMismatch = errors.New("address does not match public key")
+	// ErrPublicKeyLength - decoded public key has wrong length
+	ErrPublicKeyLength = errors.New("invalid public key length")
+	// ErrPrivateKeyLength - decoded private key has wrong length
+	ErrPrivateKeyLength = errors.New("invalid private key length")
+)
+
+// DecodeAddress - decode address in base58 check to sha3 256 hash of public key, version byte goes first.
+// Version byte of address is the first byte of hash, so it is checked against public key by ValidateAddressOfPublicKey,
+// use DecodeAddressWithVersion if version byte is known
+func DecodeAddress(address string) ([]byte, error) {
+	payload, ver, err := base58.CheckDecode(address)
+	switch {
+	case errors.Is(err, base58.ErrChecksum):
+		return nil, fmt.Errorf("%w: %s", ErrAddressChecksum, address)
+	case err != nil:
+		return nil, fmt.Errorf("%w: %s: %v", ErrAddressFormat, address, err)
+	case len(payload) != addressPayloadLen:
+		return nil, fmt.Errorf("%w: %s: %d bytes", ErrAddressLength, address, len(payload)+1)
+	}
+	return append([]byte{ver}, payload...), nil
+}
+
+// DecodeAddressWithVersion - decode address in base58 check like DecodeAddress and check that its version byte is version
+func DecodeAddressWithVersion(address string, version byte) ([]byte, error) {
+	decoded, err := DecodeAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	if decoded[0] != version {
+		return nil, fmt.Errorf("%w: %s: %d, expected %d", ErrAddressVersion, address, decoded[0], version)
+	}
+	return decoded, nil
+}
+
+// ValidateAddress - check that address in base58 check has valid checksum and length
+func ValidateAddress(address string) error {
+	_, err := DecodeAddress(address)
+	return err
+}
+
+// ValidateAddressOfPublicKey - check that address in base58 check is derived from public key
+func ValidateAddressOfPublicKey(address string, publicKey ed25519.PublicKey) error {
+	decoded, err := DecodeAddress(address)
+	if err != nil {
+		return err
+	}
+	hash := sha3.Sum256(publicKey)
+	if decoded[0] != hash[0] {
+		return fmt.Errorf("%w: %s: %d, expected %d", ErrAddressVersion, address, decoded[0], hash[0])
+	}
+	if !bytes.Equal(decoded, hash[:]) {
+		return fmt.Errorf("%w: %s", ErrAddressMismatch, address)
+	}
+	return nil
+}
+
+// GetPublicKeyFromBase58 - get public key type Ed25519 by string - Base58 encoded public key, inverse of ConvertPublicKeyToBase58
+func GetPublicKeyFromBase58(publicKeyBase58 string) (ed25519.PublicKey, error) {
+	decoded := base58.Decode(publicKeyBase58)
+	if len(decoded) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%w: %d", ErrPublicKeyLength, len(decoded))
+	}
+	return ed25519.PublicKey(decoded), nil
+}
+
+// GetAddressByPublicKeyBase58 - get address by Base58 encoded public key
+func GetAddressByPublicKeyBase58(publicKeyBase58 string) (string, error) {
+	publicKey, err := GetPublicKeyFromBase58(publicKeyBase58)
+	if err != nil {
+		return "", err
+	}
+	return GetAddressByPublicKey(publicKey)
+}
+
+// GetPrivateKeyFromHex - get private key type Ed25519 by hex string, full private key or 32 bytes seed
+func GetPrivateKeyFromHex(secretKey string) (ed25519.PrivateKey, ed25519.PublicKey, error) {
+	decoded, err := hex.DecodeString(secretKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("hex decode: %w", err)
+	}
+	return privateKeyFromBytes(decoded)
+}
+
+// GetPrivateKeyFromBase64 - get private key type Ed25519 by standard base64 string, full private key or 32 bytes seed
+func GetPrivateKeyFromBase64(secretKey string) (ed25519.PrivateKey, ed25519.PublicKey, error) {
+	decoded, err := base64.StdEncoding.DecodeString(secretKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("base64 decode: %w", err)
+	}
+	return privateKeyFromBytes(decoded)
+}
+
+// ConvertPrivateKeyToHex - encode private key type Ed25519 to hex string
+func ConvertPrivateKeyToHex(privateKey ed25519.PrivateKey) string {
+	return hex.EncodeToString(privateKey)
+}
+
+// ConvertPrivateKeyToBase64 - encode private key type Ed25519 to standard base64 string
+func ConvertPrivateKeyToBase64(privateKey ed25519.PrivateKey) string {
+	return base64.StdEncoding.EncodeToString(privateKey)
+}
+
+// ConvertPrivateKeyBase58CheckToHex - convert private key from Base58Check string to hex string
+func ConvertPrivateKeyBase58CheckToHex(secretKey string) (string, error) {
+	privateKey, _, err := GetPrivateKeyFromBase58Check(secretKey)
+	if err != nil {
+		return "", err
+	}
+	return ConvertPrivateKeyToHex(privateKey), nil
+}
+
+// ConvertPrivateKeyHexToBase58Check - convert private key from hex string to Base58Check string
+func ConvertPrivateKeyHexToBase58Check(secretKey string) (string, error) {
+	privateKey, _, err := GetPrivateKeyFromHex(secretKey)
+	if err != nil {
+		return "", err
+	}
+	return ConvertPrivateKeyToBase58Check(privateKey)
+}
+
+// InvalidAddresses - get invalid variants of valid address for negative tests, key is case name
+func InvalidAddresses(address string) map[string]string {
+	decoded := base58.Decode(address)
+	corrupted := append([]byte{}, decoded...)
+	if len(corrupted) != 0 {
+		corrupted[len(corrupted)-1] ^= 0xff
+	}
+	hash, _, _ := base58.CheckDecode(address)
+
+	return map[string]string{
+		"empty":        "",
+		"not base58":   "0OIl" + address,
+		"bad checksum": base58.Encode(corrupted),
+		"short":        base58.CheckEncode(hash[:len(hash)/2], 0),
+		"long":         base58.CheckEncode(append(append([]byte{}, hash...), hash...), 0),
+	}
+}
+
+func privateKeyFromBytes(decoded []byte) (ed25519.PrivateKey, ed25519.PublicKey, error) {
+	var privateKey ed25519.PrivateKey
+	switch len(decoded) {
+	case ed25519.SeedSize:
+		privateKey = ed25519.NewKeyFromSeed(decoded)
+	case ed25519.PrivateKeySize:
+		privateKey = ed25519.PrivateKey(decoded)
+	default:
+		return nil, nil, fmt.Errorf("%w: %d", ErrPrivateKeyLength, len(decoded))
+	}
+
+	publicKey, ok := privateKey.Public().(ed25519.PublicKey)
+	if !ok {
+		return nil, nil, errors.New("type assertion failed")
+	}
+	return privateKey, publicKey, nil
+}
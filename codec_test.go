@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/sha3"
+)
+
+func testKeys(t *testing.T) (ed25519.PrivateKey, ed25519.PublicKey, string) {
+	t.Helper()
+	privateKey, publicKey, err := DerivePrivateAndPublicKey([]byte("codec"), "user")
+	require.NoError(t, err)
+	address, err := GetAddressByPublicKey(publicKey)
+	require.NoError(t, err)
+	return privateKey, publicKey, address
+}
+
+func TestDecodeAddress(t *testing.T) {
+	_, publicKey, address := testKeys(t)
+	hash := sha3.Sum256(publicKey)
+
+	decoded, err := DecodeAddress(address)
+	require.NoError(t, err)
+	require.Equal(t, hash[:], decoded)
+
+	for name, invalid := range InvalidAddresses(address) {
+		t.Run(name, func(t *testing.T) {
+			require.Error(t, ValidateAddress(invalid))
+		})
+	}
+
+	tests := []struct {
+		name    string
+		address string
+		err     error
+	}{
+		{name: "bad checksum", address: InvalidAddresses(address)["bad checksum"], err: ErrAddressChecksum},
+		{name: "not base58", address: "", err: ErrAddressFormat},
+		{name: "short", address: InvalidAddresses(address)["short"], err: ErrAddressLength},
+		{name: "long", address: InvalidAddresses(address)["long"], err: ErrAddressLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" error", func(t *testing.T) {
+			_, err := DecodeAddress(tt.address)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestDecodeAddressWithVersion(t *testing.T) {
+	_, publicKey, address := testKeys(t)
+	hash := sha3.Sum256(publicKey)
+
+	decoded, err := DecodeAddressWithVersion(address, hash[0])
+	require.NoError(t, err)
+	require.Equal(t, hash[:], decoded)
+
+	_, err = DecodeAddressWithVersion(address, hash[0]+1)
+	require.ErrorIs(t, err, ErrAddressVersion)
+}
+
+func TestValidateAddressOfPublicKey(t *testing.T) {
+	_, publicKey, address := testKeys(t)
+	hash := sha3.Sum256(publicKey)
+	_, otherPublicKey, err := DerivePrivateAndPublicKey([]byte("codec"), "other")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		address string
+		key     ed25519.PublicKey
+		err     error
+	}{
+		{name: "valid", address: address, key: publicKey},
+		{name: "other key", address: address, key: otherPublicKey, err: ErrAddressVersion},
+		{name: "other version", address: base58.CheckEncode(hash[1:], hash[0]+1), key: publicKey, err: ErrAddressVersion},
+		{name: "other hash", address: base58.CheckEncode(append([]byte{hash[1] + 1}, hash[2:]...), hash[0]), key: publicKey, err: ErrAddressMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAddressOfPublicKey(tt.address, tt.key)
+			if tt.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestPublicKeyBase58(t *testing.T) {
+	_, publicKey, address := testKeys(t)
+
+	decoded, err := GetPublicKeyFromBase58(ConvertPublicKeyToBase58(publicKey))
+	require.NoError(t, err)
+	require.Equal(t, publicKey, decoded)
+
+	addressOfKey, err := GetAddressByPublicKeyBase58(ConvertPublicKeyToBase58(publicKey))
+	require.NoError(t, err)
+	require.Equal(t, address, addressOfKey)
+
+	_, err = GetPublicKeyFromBase58(base58.Encode(publicKey[:16]))
+	require.ErrorIs(t, err, ErrPublicKeyLength)
+}
+
+func TestPrivateKeyFormats(t *testing.T) {
+	privateKey, publicKey, _ := testKeys(t)
+	base58Check, err := ConvertPrivateKeyToBase58Check(privateKey)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		decode func(string) (ed25519.PrivateKey, ed25519.PublicKey, error)
+		key    string
+		err    error
+	}{
+		{name: "base58 check", decode: GetPrivateKeyFromBase58Check, key: base58Check},
+		{name: "hex", decode: GetPrivateKeyFromHex, key: ConvertPrivateKeyToHex(privateKey)},
+		{name: "hex seed", decode: GetPrivateKeyFromHex, key: hex.EncodeToString(privateKey.Seed())},
+		{name: "base64", decode: GetPrivateKeyFromBase64, key: ConvertPrivateKeyToBase64(privateKey)},
+		{name: "base64 seed", decode: GetPrivateKeyFromBase64, key: base64.StdEncoding.EncodeToString(privateKey.Seed())},
+		{name: "short base58 check", decode: GetPrivateKeyFromBase58Check, key: base58.CheckEncode(privateKey[1:32], privateKey[0]), err: ErrPrivateKeyLength},
+		{name: "short hex", decode: GetPrivateKeyFromHex, key: hex.EncodeToString(privateKey[:16]), err: ErrPrivateKeyLength},
+		{name: "short base64", decode: GetPrivateKeyFromBase64, key: base64.StdEncoding.EncodeToString(privateKey[:16]), err: ErrPrivateKeyLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			private, public, err := tt.decode(tt.key)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, privateKey, private)
+			require.Equal(t, publicKey, public)
+		})
+	}
+
+	hexKey, err := ConvertPrivateKeyBase58CheckToHex(base58Check)
+	require.NoError(t, err)
+	require.Equal(t, ConvertPrivateKeyToHex(privateKey), hexKey)
+	converted, err := ConvertPrivateKeyHexToBase58Check(hexKey)
+	require.NoError(t, err)
+	require.Equal(t, base58Check, converted)
+
+	_, err = ConvertPrivateKeyToBase58Check(privateKey[:32])
+	require.ErrorIs(t, err, ErrPrivateKeyLength)
+}
@@ -0,0 +1,208 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// ConsistencyTimeout - time to wait until all peers return same query result
+	ConsistencyTimeout = 30 * time.Second
+	// ConsistencyPollInterval - interval between queries to peers while waiting for same result
+	ConsistencyPollInterval = 500 * time.Millisecond
+
+	// QSCC - system chaincode of peer ledger queries
+	QSCC = "qscc"
+	// QSCCGetChainInfo - qscc function returning protobuf common.BlockchainInfo of channel
+	QSCCGetChainInfo = "GetChainInfo"
+)
+
+// ErrPeersInconsistent - peers returned different query results
+var ErrPeersInconsistent = errors.New("peers are inconsistent")
+
+// EndpointsClient - ChaincodeClient sending requests to chosen peers, implemented by HlfProxyService
+type EndpointsClient interface {
+	ChaincodeClient
+	ClientOnEndpoints(endpoints ...string) ChaincodeClient
+}
+
+var _ EndpointsClient = (*HlfProxyService)(nil)
+
+// PeerQueryResult struct for result of query sent to one peer
+// BlockHeight - height of ledger of peer from qscc GetChainInfo queried after query, HeightError if it failed
+// Differs - payload differs from payload returned by most of peers or query failed
+type PeerQueryResult struct {
+	Endpoint    string `json:"endpoint"`
+	Payload     string `json:"payload,omitempty"`
+	BlockHeight uint64 `json:"blockHeight,omitempty"`
+	HeightError string `json:"heightError,omitempty"`
+	Error       string `json:"error,omitempty"`
+	Differs     bool   `json:"differs"`
+}
+
+// ConsistencyReport struct for result of comparing query results of peers
+// Payload - payload returned by most of peers
+// Attempts - number of times query was sent to all peers
+type ConsistencyReport struct {
+	Channel   string            `json:"channel"`
+	Fcn       string            `json:"fcn"`
+	Args      []string          `json:"args"`
+	Converged bool              `json:"converged"`
+	Attempts  int               `json:"attempts"`
+	Payload   string            `json:"payload"`
+	Peers     []PeerQueryResult `json:"peers"`
+}
+
+// String returns peers with payload different from payload of most of peers
+func (r *ConsistencyReport) String() string {
+	var diffs []string
+	for _, peer := range r.Peers {
+		if !peer.Differs {
+			continue
+		}
+		if peer.Error != "" {
+			diffs = append(diffs, fmt.Sprintf("%s: error %s", peer.Endpoint, peer.Error))
+			continue
+		}
+		diffs = append(diffs, fmt.Sprintf("%s: %s at height %d", peer.Endpoint, peer.Payload, peer.BlockHeight))
+	}
+	if len(diffs) == 0 {
+		return fmt.Sprintf("%s %s: all %d peers returned %s", r.Channel, r.Fcn, len(r.Peers), r.Payload)
+	}
+	return fmt.Sprintf("%s %s: expected %s, %s", r.Channel, r.Fcn, r.Payload, strings.Join(diffs, ", "))
+}
+
+// QueryEndpoints sends same query to every peer of endpoints and compares results, block height of every peer is added to report
+func QueryEndpoints(ctx context.Context, hlfProxy EndpointsClient, endpoints []string, channel string, fcn string, args ...string) *ConsistencyReport {
+	report := &ConsistencyReport{
+		Channel: channel,
+		Fcn:     fcn,
+		Args:    RedactArgs(args),
+		Peers:   make([]PeerQueryResult, len(endpoints)),
+	}
+
+	counts := make(map[string]int)
+	for i, endpoint := range endpoints {
+		report.Peers[i].Endpoint = endpoint
+		client := hlfProxy.ClientOnEndpoints(endpoint)
+		resp, err := client.QueryContext(ctx, channel, fcn, args...)
+		if err != nil {
+			report.Peers[i].Error = err.Error()
+			continue
+		}
+		report.Peers[i].Payload = string(resp.Payload)
+		counts[report.Peers[i].Payload]++
+
+		if report.Peers[i].BlockHeight, err = GetBlockHeight(ctx, client, channel); err != nil {
+			report.Peers[i].HeightError = err.Error()
+		}
+	}
+
+	for payload, count := range counts {
+		if count > counts[report.Payload] || (count == counts[report.Payload] && payload < report.Payload) {
+			report.Payload = payload
+		}
+	}
+
+	report.Converged = len(endpoints) != 0
+	for i := range report.Peers {
+		report.Peers[i].Differs = report.Peers[i].Error != "" || report.Peers[i].Payload != report.Payload
+		if report.Peers[i].Differs {
+			report.Converged = false
+		}
+	}
+
+	return report
+}
+
+// GetBlockHeight returns height of ledger of channel by qscc GetChainInfo, use ClientOnEndpoints to get height of one peer
+func GetBlockHeight(ctx context.Context, hlfProxy ChaincodeClient, channel string) (uint64, error) {
+	resp, err := hlfProxy.QueryContext(ctx, QSCC, QSCCGetChainInfo, channel)
+	if err != nil {
+		return 0, err
+	}
+	return parseChainInfoHeight(resp.Payload)
+}
+
+// parseChainInfoHeight returns field height number 1 of protobuf common.BlockchainInfo
+func parseChainInfoHeight(payload []byte) (uint64, error) {
+	for len(payload) > 0 {
+		num, typ, n := protowire.ConsumeTag(payload)
+		if n < 0 {
+			return 0, fmt.Errorf("parse chain info: %w", protowire.ParseError(n))
+		}
+		payload = payload[n:]
+		if num == 1 && typ == protowire.VarintType {
+			height, m := protowire.ConsumeVarint(payload)
+			if m < 0 {
+				return 0, fmt.Errorf("parse chain info height: %w", protowire.ParseError(m))
+			}
+			return height, nil
+		}
+		m := protowire.ConsumeFieldValue(num, typ, payload)
+		if m < 0 {
+			return 0, fmt.Errorf("parse chain info: %w", protowire.ParseError(m))
+		}
+		payload = payload[m:]
+	}
+	return 0, errors.New("parse chain info: no height")
+}
+
+// WaitConsistent queries every peer of endpoints until all of them return same payload,
+// returns last report together with ErrPeersInconsistent if peers do not converge in ConsistencyTimeout
+func WaitConsistent(ctx context.Context, hlfProxy EndpointsClient, endpoints []string, channel string, fcn string, args ...string) (*ConsistencyReport, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("no endpoints to compare")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ConsistencyTimeout)
+	defer cancel()
+
+	for attempt := 1; ; attempt++ {
+		report := QueryEndpoints(ctx, hlfProxy, endpoints, channel, fcn, args...)
+		report.Attempts = attempt
+		if report.Converged {
+			return report, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return report, fmt.Errorf("%w: %s", ErrPeersInconsistent, report)
+		case <-time.After(ConsistencyPollInterval):
+		}
+	}
+}
+
+// CheckConsistent checks that every peer of endpoints returns same payload of query, waiting until they converge.
+// Report with payloads and block heights of peers is attached to step, returns payload
+func CheckConsistent(t provider.T, hlfProxy EndpointsClient, endpoints []string, channel string, fcn string, args ...string) []byte {
+	var payload []byte
+	t.WithNewStep("Checking that peers return same result of "+fcn+" in channel "+channel, func(sCtx provider.StepCtx) {
+		report, err := WaitConsistent(context.Background(), hlfProxy, endpoints, channel, fcn, args...)
+		if report != nil {
+			if data, mErr := json.MarshalIndent(report, "", "  "); mErr == nil {
+				sCtx.WithNewAttachment("peers consistency", allure.JSON, data)
+			}
+		}
+		sCtx.Require().NoError(err)
+		payload = []byte(report.Payload)
+	})
+	return payload
+}
+
+// CheckBalanceEqualOnAllPeers checks that balance of userAddressBase58Check is equal to amount on every peer of endpoints,
+// waiting until peers converge
+func CheckBalanceEqualOnAllPeers(t provider.T, hlfProxy EndpointsClient, endpoints []string, userAddressBase58Check string, channel string, amount string) {
+	payload := CheckConsistent(t, hlfProxy, endpoints, channel, "balanceOf", userAddressBase58Check)
+	t.WithNewStep("Checking that balance equal "+amount+" on all peers", func(sCtx provider.StepCtx) {
+		sCtx.Require().Equal("\""+amount+"\"", string(payload))
+	})
+}
@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// peer struct for state of stub peer
+type peer struct {
+	payload string
+	height  uint64
+	err     error
+}
+
+// endpointsStub - EndpointsClient answering queries by state of peer request is sent to
+type endpointsStub struct {
+	peers    map[string]peer
+	endpoint string
+}
+
+func (s endpointsStub) ClientOnEndpoints(endpoints ...string) ChaincodeClient {
+	s.endpoint = endpoints[0]
+	return s
+}
+
+func (s endpointsStub) InvokeContext(context.Context, string, string, ...string) (*Response, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s endpointsStub) QueryContext(_ context.Context, chaincodeID string, fcn string, _ ...string) (*Response, error) {
+	p := s.peers[s.endpoint]
+	if p.err != nil {
+		return nil, p.err
+	}
+	if chaincodeID == QSCC && fcn == QSCCGetChainInfo {
+		return &Response{Payload: chainInfo(p.height)}, nil
+	}
+	return &Response{Payload: []byte(p.payload)}, nil
+}
+
+// chainInfo returns protobuf common.BlockchainInfo with height and current block hash
+func chainInfo(height uint64) []byte {
+	b := protowire.AppendTag(nil, 2, protowire.BytesType)
+	b = protowire.AppendBytes(b, []byte("hash"))
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	return protowire.AppendVarint(b, height)
+}
+
+func TestParseChainInfoHeight(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload []byte
+		height  uint64
+		err     bool
+	}{
+		{name: "height after other field", payload: chainInfo(42), height: 42},
+		{name: "height only", payload: protowire.AppendVarint(protowire.AppendTag(nil, 1, protowire.VarintType), 7), height: 7},
+		{name: "no height", payload: protowire.AppendBytes(protowire.AppendTag(nil, 2, protowire.BytesType), []byte("hash")), err: true},
+		{name: "empty", err: true},
+		{name: "truncated", payload: protowire.AppendTag(nil, 1, protowire.VarintType), err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			height, err := parseChainInfoHeight(tt.payload)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.height, height)
+		})
+	}
+}
+
+func TestQueryEndpoints(t *testing.T) {
+	endpoints := []string{"peer0", "peer1", "peer2"}
+
+	tests := []struct {
+		name      string
+		peers     map[string]peer
+		converged bool
+		payload   string
+		differs   []bool
+	}{
+		{
+			name:      "consistent",
+			peers:     map[string]peer{"peer0": {"\"10\"", 5, nil}, "peer1": {"\"10\"", 5, nil}, "peer2": {"\"10\"", 6, nil}},
+			converged: true,
+			payload:   "\"10\"",
+			differs:   []bool{false, false, false},
+		},
+		{
+			name:    "one peer behind",
+			peers:   map[string]peer{"peer0": {"\"10\"", 5, nil}, "peer1": {"\"0\"", 4, nil}, "peer2": {"\"10\"", 5, nil}},
+			payload: "\"10\"",
+			differs: []bool{false, true, false},
+		},
+		{
+			name:    "one peer fails",
+			peers:   map[string]peer{"peer0": {"\"10\"", 5, nil}, "peer1": {"\"10\"", 5, nil}, "peer2": {err: errors.New("unavailable")}},
+			payload: "\"10\"",
+			differs: []bool{false, false, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := QueryEndpoints(context.Background(), endpointsStub{peers: tt.peers}, endpoints, "fiat", "balanceOf", "address")
+			require.Equal(t, tt.converged, report.Converged)
+			require.Equal(t, tt.payload, report.Payload)
+			for i, endpoint := range endpoints {
+				require.Equal(t, endpoint, report.Peers[i].Endpoint)
+				require.Equal(t, tt.differs[i], report.Peers[i].Differs, endpoint)
+				if tt.peers[endpoint].err == nil {
+					require.Equal(t, tt.peers[endpoint].height, report.Peers[i].BlockHeight, endpoint)
+				}
+			}
+		})
+	}
+}
+
+func TestWaitConsistent(t *testing.T) {
+	consistent := endpointsStub{peers: map[string]peer{"peer0": {payload: "1"}, "peer1": {payload: "1"}}}
+	report, err := WaitConsistent(context.Background(), consistent, []string{"peer0", "peer1"}, "fiat", "balanceOf")
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Attempts)
+
+	inconsistent := endpointsStub{peers: map[string]peer{"peer0": {payload: "1"}, "peer1": {payload: "2"}}}
+	ctx, cancel := context.WithTimeout(context.Background(), ConsistencyPollInterval+100*time.Millisecond)
+	defer cancel()
+	report, err = WaitConsistent(ctx, inconsistent, []string{"peer0", "peer1"}, "fiat", "balanceOf")
+	require.ErrorIs(t, err, ErrPeersInconsistent)
+	require.False(t, report.Converged)
+
+	_, err = WaitConsistent(context.Background(), consistent, nil, "fiat", "balanceOf")
+	require.Error(t, err)
+}
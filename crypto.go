@@ -123,6 +123,9 @@ func GetPrivateKeyFromBase58Check(secretKey string) (ed25519.PrivateKey, ed25519
 	if err != nil {
 		return nil, nil, fmt.Errorf("check decode: %w", err)
 	}
+	if len(decode)+1 != ed25519.PrivateKeySize {
+		return nil, nil, fmt.Errorf("%w: %d", ErrPrivateKeyLength, len(decode)+1)
+	}
 	privateKey := ed25519.PrivateKey(append([]byte{ver}, decode...))
 	publicKey, ok := privateKey.Public().(ed25519.PublicKey)
 	if !ok {
@@ -131,6 +134,14 @@ func GetPrivateKeyFromBase58Check(secretKey string) (ed25519.PrivateKey, ed25519
 	return privateKey, publicKey, nil
 }
 
+// ConvertPrivateKeyToBase58Check - encode private key type Ed25519 to Base58Check string, inverse of GetPrivateKeyFromBase58Check
+func ConvertPrivateKeyToBase58Check(privateKey ed25519.PrivateKey) (string, error) {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return "", fmt.Errorf("%w: %d", ErrPrivateKeyLength, len(privateKey))
+	}
+	return base58.CheckEncode(privateKey[1:], privateKey[0]), nil
+}
+
 // ConvertPublicKeyToBase58 - use publicKey with standard encoded type - Base58
 func ConvertPublicKeyToBase58(publicKey ed25519.PublicKey) string {
 	return base58.Encode(publicKey)
@@ -0,0 +1,345 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+// Deal types of token exchange rates
+const (
+	DealTypeBuyToken = "buyToken"
+	DealTypeBuyBack  = "buyBack"
+)
+
+// RateDecimals - number of decimals of exchange rate, rate 100000000 means 1 currency token for 1 token
+const RateDecimals = 8
+
+var (
+	// ErrRateNotFound - token has no exchange rate for deal type and currency
+	ErrRateNotFound = errors.New("rate not found")
+	// ErrAmountOutOfLimits - amount is less than min or greater than max of exchange rate
+	ErrAmountOutOfLimits = errors.New("amount out of limits")
+)
+
+// TokenMetadata struct for token metadata returned by chaincode query metadata
+type TokenMetadata struct {
+	Name            string    `json:"name"`
+	Symbol          string    `json:"symbol"`
+	Decimals        uint      `json:"decimals"`
+	UnderlyingAsset string    `json:"underlying_asset"`
+	Issuer          string    `json:"issuer"`
+	Methods         []string  `json:"methods"`
+	TotalEmission   *big.Int  `json:"total_emission"`
+	Fee             *TokenFee `json:"fee"`
+	Rates           []Rate    `json:"rates"`
+}
+
+// Rate struct for exchange rate of token
+// Rate - price of one token in currency tokens multiplied by 10^RateDecimals
+// Min, Max - limits of amount of tokens in one deal, no limit if zero
+type Rate struct {
+	DealType string   `json:"deal_type"`
+	Currency string   `json:"currency"`
+	Rate     *big.Int `json:"rate"`
+	Min      *big.Int `json:"min"`
+	Max      *big.Int `json:"max"`
+}
+
+// GetMetadata returns metadata of token in channel
+func GetMetadata(hlfProxy ChaincodeClient, channel string) (*TokenMetadata, error) {
+	return GetMetadataContext(context.Background(), hlfProxy, channel)
+}
+
+// GetMetadataContext - GetMetadata with context
+func GetMetadataContext(ctx context.Context, hlfProxy ChaincodeClient, channel string) (*TokenMetadata, error) {
+	resp, err := hlfProxy.QueryContext(ctx, channel, "metadata")
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &TokenMetadata{}
+	if err = json.Unmarshal(resp.Payload, metadata); err != nil {
+		return nil, fmt.Errorf("json unmarshal metadata: %w", err)
+	}
+	return metadata, nil
+}
+
+// Rate returns exchange rate for deal type and currency, ErrRateNotFound if there is no such rate
+func (m *TokenMetadata) Rate(dealType string, currency string) (Rate, error) {
+	for _, rate := range m.Rates {
+		if rate.DealType == dealType && rate.Currency == currency {
+			return rate, nil
+		}
+	}
+	return Rate{}, fmt.Errorf("%w: %s %s", ErrRateNotFound, dealType, currency)
+}
+
+// GetRate returns current exchange rate of token in channel for deal type and currency from metadata
+func GetRate(hlfProxy ChaincodeClient, channel string, dealType string, currency string) (Rate, error) {
+	return GetRateContext(context.Background(), hlfProxy, channel, dealType, currency)
+}
+
+// GetRateContext - GetRate with context
+func GetRateContext(ctx context.Context, hlfProxy ChaincodeClient, channel string, dealType string, currency string) (Rate, error) {
+	metadata, err := GetMetadataContext(ctx, hlfProxy, channel)
+	if err != nil {
+		return Rate{}, err
+	}
+	return metadata.Rate(dealType, currency)
+}
+
+// Price returns price of amount of tokens in currency tokens, ErrAmountOutOfLimits if amount is out of limits of rate
+func (r Rate) Price(amount *big.Int) (*big.Int, error) {
+	if r.Rate == nil {
+		return nil, fmt.Errorf("%w: %s %s", ErrRateNotFound, r.DealType, r.Currency)
+	}
+	if (r.Min != nil && amount.Cmp(r.Min) < 0) || (r.Max != nil && r.Max.Sign() > 0 && amount.Cmp(r.Max) > 0) {
+		return nil, fmt.Errorf("%w: amount %s, min %s, max %s", ErrAmountOutOfLimits, amount, r.Min, r.Max)
+	}
+	price := new(big.Int).Mul(amount, r.Rate)
+	return price.Div(price, new(big.Int).Exp(big.NewInt(10), big.NewInt(RateDecimals), nil)), nil //nolint:gomnd
+}
+
+// SetRate signs by issuer and invokes setting exchange rate for deal type and currency.
+// rate - price of one token in currency tokens multiplied by 10^RateDecimals
+func SetRate(hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string, dealType string, currency string, rate string) (*Response, error) {
+	return invokeByIssuer(hlfProxy, issuer, channel, chaincode, "setRate", dealType, currency, rate)
+}
+
+// UpdateRate sets new value of existing exchange rate for deal type and currency keeping its limits:
+// limits are read from metadata before setting rate and set again if they were set.
+// Returns ErrRateNotFound if there is no such rate, response of last invoke otherwise
+func UpdateRate(hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string, dealType string, currency string, rate string) (*Response, error) {
+	current, err := GetRate(hlfProxy, channel, dealType, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := SetRate(hlfProxy, issuer, channel, chaincode, dealType, currency, rate)
+	if err != nil || !limitSet(current.Min) && !limitSet(current.Max) {
+		return resp, err
+	}
+
+	return SetLimits(hlfProxy, issuer, channel, chaincode, dealType, currency, limitString(current.Min), limitString(current.Max))
+}
+
+func limitSet(limit *big.Int) bool {
+	return limit != nil && limit.Sign() != 0
+}
+
+func limitString(limit *big.Int) string {
+	if limit == nil {
+		return "0"
+	}
+	return limit.String()
+}
+
+// DeleteRate signs by issuer and invokes deletion of exchange rate for deal type and currency
+func DeleteRate(hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string, dealType string, currency string) (*Response, error) {
+	return invokeByIssuer(hlfProxy, issuer, channel, chaincode, "deleteRate", dealType, currency)
+}
+
+// SetLimits signs by issuer and invokes setting limits of amount of tokens in one deal for deal type and currency, max "0" means no limit
+func SetLimits(hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string, dealType string, currency string, min string, max string) (*Response, error) {
+	return invokeByIssuer(hlfProxy, issuer, channel, chaincode, "setLimits", dealType, currency, min, max)
+}
+
+// BuyToken signs by user and invokes buying amount of tokens for currency tokens from allowed balance of user
+func BuyToken(hlfProxy ChaincodeClient, user User, channel string, chaincode string, amount string, currency string) (*Response, error) {
+	return InvokeByUser(hlfProxy, user, channel, chaincode, DealTypeBuyToken, amount, currency)
+}
+
+// BuyBack signs by user and invokes selling amount of tokens back to issuer for currency tokens
+func BuyBack(hlfProxy ChaincodeClient, user User, channel string, chaincode string, amount string, currency string) (*Response, error) {
+	return InvokeByUser(hlfProxy, user, channel, chaincode, DealTypeBuyBack, amount, currency)
+}
+
+// SetRateAndCheck sets exchange rate for deal type and currency and checks that metadata contains it
+func SetRateAndCheck(
+	t provider.T,
+	hlfProxy ChaincodeClient,
+	issuer Issuer,
+	channel string,
+	chaincode string,
+	dealType string,
+	currency string,
+	rate string,
+) {
+	t.WithNewStep("Set rate "+rate+" of "+dealType+" for "+currency, func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		_, err := SetRate(hlfProxy, issuer, channel, chaincode, dealType, currency, rate)
+		sCtx.Require().NoError(err)
+		time.Sleep(BatchTransactionTimeout)
+	})
+
+	CheckRateEqual(t, hlfProxy, channel, dealType, currency, rate)
+}
+
+// CheckRateEqual checks that exchange rate for deal type and currency in metadata is equal to rate
+func CheckRateEqual(t provider.T, hlfProxy ChaincodeClient, channel string, dealType string, currency string, rate string) {
+	t.WithNewStep("Checking that rate of "+dealType+" for "+currency+" equal "+rate, func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		r, err := GetRate(hlfProxy, channel, dealType, currency)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal(rate, r.Rate.String())
+	})
+}
+
+// CheckRateDeleted checks that metadata has no exchange rate for deal type and currency
+func CheckRateDeleted(t provider.T, hlfProxy ChaincodeClient, channel string, dealType string, currency string) {
+	t.WithNewStep("Checking that rate of "+dealType+" for "+currency+" is deleted", func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		_, err := GetRate(hlfProxy, channel, dealType, currency)
+		sCtx.Require().ErrorIs(err, ErrRateNotFound)
+	})
+}
+
+// BuyTokenAndCheckBalances buys amount of tokens for currency and checks that balance of user is increased by amount
+// and allowed balance of currency is decreased by price calculated by current rate
+func BuyTokenAndCheckBalances(
+	t provider.T,
+	hlfProxy ChaincodeClient,
+	user User,
+	channel string,
+	chaincode string,
+	amount string,
+	currency string,
+) *Response {
+	return exchangeAndCheckBalances(t, hlfProxy, user, channel, chaincode, DealTypeBuyToken, amount, currency)
+}
+
+// BuyBackAndCheckBalances sells amount of tokens for currency and checks that balance of user is decreased by amount
+// and allowed balance of currency is increased by price calculated by current rate
+func BuyBackAndCheckBalances(
+	t provider.T,
+	hlfProxy ChaincodeClient,
+	user User,
+	channel string,
+	chaincode string,
+	amount string,
+	currency string,
+) *Response {
+	return exchangeAndCheckBalances(t, hlfProxy, user, channel, chaincode, DealTypeBuyBack, amount, currency)
+}
+
+// CheckExchangeOutOfLimits checks that deal of amount of tokens is out of limits of current rate and is rejected
+func CheckExchangeOutOfLimits(
+	t provider.T,
+	hlfProxy ChaincodeClient,
+	user User,
+	channel string,
+	chaincode string,
+	dealType string,
+	amount string,
+	currency string,
+) {
+	t.WithNewStep("Checking that "+dealType+" of "+amount+" for "+currency+" is out of limits", func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		rate, err := GetRate(hlfProxy, channel, dealType, currency)
+		sCtx.Require().NoError(err)
+		_, err = rate.Price(parseAmount(sCtx, amount))
+		sCtx.Require().ErrorIs(err, ErrAmountOutOfLimits)
+
+		_, err = InvokeByUser(hlfProxy, user, channel, chaincode, dealType, amount, currency)
+		sCtx.Require().Error(err)
+	})
+}
+
+func exchangeAndCheckBalances(
+	t provider.T,
+	hlfProxy ChaincodeClient,
+	user User,
+	channel string,
+	chaincode string,
+	dealType string,
+	amount string,
+	currency string,
+) *Response {
+	var res *Response
+	address := user.UserAddressBase58Check
+	t.WithNewStep(dealType+" "+amount+" for "+currency+" by user "+address, func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		rate, err := GetRate(hlfProxy, channel, dealType, currency)
+		sCtx.Require().NoError(err)
+		value := parseAmount(sCtx, amount)
+		price, err := rate.Price(value)
+		sCtx.Require().NoError(err)
+		sCtx.WithNewParameters("rate", rate.Rate.String(), "price", price.String())
+
+		balance, err := QueryAmount(hlfProxy, channel, "balanceOf", address)
+		sCtx.Require().NoError(err)
+		allowed, err := QueryAmount(hlfProxy, channel, "allowedBalanceOf", address, currency)
+		sCtx.Require().NoError(err)
+
+		res, err = InvokeByUser(hlfProxy, user, channel, chaincode, dealType, amount, currency)
+		sCtx.Require().NoError(err)
+		time.Sleep(BatchTransactionTimeout)
+
+		if dealType == DealTypeBuyBack {
+			value.Neg(value)
+			price.Neg(price)
+		}
+		expectedBalance := new(big.Int).Add(balance, value)
+		expectedAllowed := new(big.Int).Sub(allowed, price)
+
+		balance, err = QueryAmount(hlfProxy, channel, "balanceOf", address)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal(expectedBalance.String(), balance.String(), "balance")
+		allowed, err = QueryAmount(hlfProxy, channel, "allowedBalanceOf", address, currency)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal(expectedAllowed.String(), allowed.String(), "allowed balance of %s", currency)
+	})
+	return res
+}
+
+// QueryAmount queries chaincode method returning amount as json string, example balanceOf
+func QueryAmount(hlfProxy ChaincodeClient, channel string, fcn string, args ...string) (*big.Int, error) {
+	return QueryAmountContext(context.Background(), hlfProxy, channel, fcn, args...)
+}
+
+// QueryAmountContext - QueryAmount with context
+func QueryAmountContext(ctx context.Context, hlfProxy ChaincodeClient, channel string, fcn string, args ...string) (*big.Int, error) {
+	resp, err := hlfProxy.QueryContext(ctx, channel, fcn, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var s string
+	if err = json.Unmarshal(resp.Payload, &s); err != nil {
+		return nil, fmt.Errorf("json unmarshal amount: %w", err)
+	}
+	amount, ok := new(big.Int).SetString(s, 10) //nolint:gomnd
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	return amount, nil
+}
+
+func parseAmount(sCtx provider.StepCtx, amount string) *big.Int {
+	value, ok := new(big.Int).SetString(amount, 10) //nolint:gomnd
+	sCtx.Require().True(ok, "invalid amount %q", amount)
+	return value
+}
+
+func invokeByIssuer(hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string, fcn string, args ...string) (*Response, error) {
+	signedArgs, err := Sign(issuer.IssuerEd25519PrivateKey, issuer.IssuerEd25519PublicKey, channel, chaincode, fcn, args)
+	if err != nil {
+		return nil, err
+	}
+	return hlfProxy.InvokeContext(context.Background(), channel, fcn, signedArgs...)
+}
+
+// InvokeByUser invokes method fcn of chaincode with args signed by user
+func InvokeByUser(hlfProxy ChaincodeClient, user User, channel string, chaincode string, fcn string, args ...string) (*Response, error) {
+	signedArgs, err := Sign(user.UserEd25519PrivateKey, user.UserEd25519PublicKey, channel, chaincode, fcn, args)
+	if err != nil {
+		return nil, err
+	}
+	return hlfProxy.InvokeContext(context.Background(), channel, fcn, signedArgs...)
+}
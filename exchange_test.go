@@ -0,0 +1,137 @@
+package utils_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	utils "github.com/anoideaopen/testnet-util"
+	"github.com/anoideaopen/testnet-util/fake"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateRate(t *testing.T) {
+	_, issuer := newFakeClient(t)
+
+	tests := []struct {
+		name     string
+		metadata string
+		limits   []string
+		err      error
+	}{
+		{
+			name:     "limits are kept",
+			metadata: `{"rates":[{"deal_type":"buyToken","currency":"FIAT","rate":100000000,"min":10,"max":1000}]}`,
+			limits:   []string{"10", "1000"},
+		},
+		{
+			name:     "min only",
+			metadata: `{"rates":[{"deal_type":"buyToken","currency":"FIAT","rate":100000000,"min":10,"max":0}]}`,
+			limits:   []string{"10", "0"},
+		},
+		{
+			name:     "no limits",
+			metadata: `{"rates":[{"deal_type":"buyToken","currency":"FIAT","rate":100000000}]}`,
+		},
+		{
+			name:     "not found",
+			metadata: `{"rates":[{"deal_type":"buyBack","currency":"FIAT","rate":100000000}]}`,
+			err:      utils.ErrRateNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok := func(context.Context, []string) ([]byte, error) { return nil, nil }
+			client := fake.NewClient().
+				Handle("cc", "metadata", func(context.Context, []string) ([]byte, error) { return []byte(tt.metadata), nil }).
+				Handle("cc", "setRate", ok).
+				Handle("cc", "setLimits", ok)
+			recorder := fake.NewRecorder(client)
+
+			_, err := utils.UpdateRate(recorder, issuer, "cc", "cc", utils.DealTypeBuyToken, "FIAT", "200000000")
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				require.Empty(t, recorder.CallsOf("setRate"))
+				return
+			}
+			require.NoError(t, err)
+
+			setRate := recorder.CallsOf("setRate")
+			require.Len(t, setRate, 1)
+			require.Equal(t, []string{utils.DealTypeBuyToken, "FIAT", "200000000"}, setRate[0].Args[3:6])
+
+			setLimits := recorder.CallsOf("setLimits")
+			if tt.limits == nil {
+				require.Empty(t, setLimits)
+				return
+			}
+			require.Len(t, setLimits, 1)
+			require.Equal(t, append([]string{utils.DealTypeBuyToken, "FIAT"}, tt.limits...), setLimits[0].Args[3:7])
+		})
+	}
+}
+
+func TestRatePrice(t *testing.T) {
+	// oneAndHalf - rate 1.5 multiplied by 10^RateDecimals
+	oneAndHalf := big.NewInt(150000000)
+
+	tests := []struct {
+		name   string
+		rate   utils.Rate
+		amount int64
+		price  string
+		err    error
+	}{
+		{name: "price", rate: utils.Rate{Rate: oneAndHalf}, amount: 10, price: "15"},
+		{name: "rounding down", rate: utils.Rate{Rate: oneAndHalf}, amount: 3, price: "4"},
+		{name: "within limits", rate: utils.Rate{Rate: oneAndHalf, Min: big.NewInt(2), Max: big.NewInt(10)}, amount: 10, price: "15"},
+		{name: "zero max means no max", rate: utils.Rate{Rate: oneAndHalf, Max: big.NewInt(0)}, amount: 1000, price: "1500"},
+		{name: "less than min", rate: utils.Rate{Rate: oneAndHalf, Min: big.NewInt(2)}, amount: 1, err: utils.ErrAmountOutOfLimits},
+		{name: "greater than max", rate: utils.Rate{Rate: oneAndHalf, Max: big.NewInt(10)}, amount: 11, err: utils.ErrAmountOutOfLimits},
+		{name: "rate is not set", rate: utils.Rate{DealType: utils.DealTypeBuyToken, Currency: "FIAT"}, amount: 1, err: utils.ErrRateNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, err := tt.rate.Price(big.NewInt(tt.amount))
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.price, price.String())
+		})
+	}
+}
+
+func TestQueryAmount(t *testing.T) {
+	payload := func(p string) fake.Handler {
+		return func(context.Context, []string) ([]byte, error) { return []byte(p), nil }
+	}
+	client := fake.NewClient().
+		Handle("fiat", "amount", payload(`"123456789012345678901234567890"`)).
+		Handle("fiat", "number", payload(`100`)).
+		Handle("fiat", "text", payload(`"abc"`))
+
+	tests := []struct {
+		name   string
+		fcn    string
+		amount string
+		err    string
+	}{
+		{name: "amount in json string", fcn: "amount", amount: "123456789012345678901234567890"},
+		{name: "json number", fcn: "number", err: "json unmarshal amount"},
+		{name: "not a number", fcn: "text", err: `invalid amount "abc"`},
+		{name: "query error", fcn: "unknown", err: fake.ErrUnknownMethod.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, err := utils.QueryAmount(client, "fiat", tt.fcn)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.amount, amount.String())
+		})
+	}
+}
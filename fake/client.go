@@ -0,0 +1,85 @@
+// Package fake contains in-memory and recording implementations of utils.ChaincodeClient
+// to run suites and helpers without stand
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	utils "github.com/anoideaopen/testnet-util"
+)
+
+// ErrUnknownMethod - no handler is registered for chaincode and method
+var ErrUnknownMethod = errors.New("unknown method")
+
+// Handler - handler of chaincode method, returns payload of response or error returned by chaincode.
+// args - arguments of request as they are sent, including signature for signed methods
+type Handler func(ctx context.Context, args []string) ([]byte, error)
+
+// Client - in-memory utils.ChaincodeClient calling handlers registered by chaincode and method.
+// Every invoke gets new transaction id and block number, queries get current block number
+type Client struct {
+	mu       sync.Mutex
+	handlers map[string]map[string]Handler
+	block    int64
+}
+
+var _ utils.ChaincodeClient = (*Client)(nil)
+
+// NewClient - create new instance of Client without handlers
+func NewClient() *Client {
+	return &Client{handlers: make(map[string]map[string]Handler)}
+}
+
+// Handle - register handler of method fcn of chaincode chaincodeID, previous handler is replaced
+func (c *Client) Handle(chaincodeID string, fcn string, handler Handler) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.handlers[chaincodeID] == nil {
+		c.handlers[chaincodeID] = make(map[string]Handler)
+	}
+	c.handlers[chaincodeID][fcn] = handler
+	return c
+}
+
+// InvokeContext - call handler as transaction committed in new block
+func (c *Client) InvokeContext(ctx context.Context, chaincodeID string, fcn string, args ...string) (*utils.Response, error) {
+	return c.call(ctx, true, chaincodeID, fcn, args)
+}
+
+// QueryContext - call handler without new block
+func (c *Client) QueryContext(ctx context.Context, chaincodeID string, fcn string, args ...string) (*utils.Response, error) {
+	return c.call(ctx, false, chaincodeID, fcn, args)
+}
+
+func (c *Client) call(ctx context.Context, invoke bool, chaincodeID string, fcn string, args []string) (*utils.Response, error) {
+	c.mu.Lock()
+	handler, ok := c.handlers[chaincodeID][fcn]
+	if ok && invoke {
+		c.block++
+	}
+	block := c.block
+	c.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %s", ErrUnknownMethod, chaincodeID, fcn)
+	}
+
+	payload, err := handler(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &utils.Response{
+		BlockNumber: block,
+		Payload:     payload,
+	}
+	if invoke {
+		resp.TransactionID = chaincodeID + "-" + strconv.FormatInt(block, 10)
+	}
+	return resp, nil
+}
@@ -0,0 +1,93 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient(t *testing.T) {
+	errHandler := errors.New("handler error")
+	client := NewClient().
+		Handle("cc", "ok", func(_ context.Context, args []string) ([]byte, error) { return []byte(args[0]), nil }).
+		Handle("cc", "fail", func(context.Context, []string) ([]byte, error) { return nil, errHandler })
+
+	tests := []struct {
+		name    string
+		invoke  bool
+		fcn     string
+		payload string
+		txID    string
+		block   int64
+		err     error
+	}{
+		{name: "invoke gets new block", invoke: true, fcn: "ok", payload: "a", txID: "cc-1", block: 1},
+		{name: "query gets current block", fcn: "ok", payload: "a", block: 1},
+		{name: "next invoke", invoke: true, fcn: "ok", payload: "a", txID: "cc-2", block: 2},
+		{name: "handler error", invoke: true, fcn: "fail", err: errHandler},
+		{name: "unknown method", invoke: true, fcn: "unknown", err: ErrUnknownMethod},
+		{name: "unknown method doesn't get block", fcn: "ok", payload: "a", block: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call := client.QueryContext
+			if tt.invoke {
+				call = client.InvokeContext
+			}
+			resp, err := call(context.Background(), "cc", tt.fcn, "a")
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.payload, string(resp.Payload))
+			require.Equal(t, tt.txID, resp.TransactionID)
+			require.Equal(t, tt.block, resp.BlockNumber)
+			require.NoError(t, resp.CheckValid())
+		})
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	client := NewClient().Handle("cc", "ok", func(context.Context, []string) ([]byte, error) { return nil, nil })
+	recorder := NewRecorder(client)
+
+	args := []string{"a"}
+	_, err := recorder.InvokeContext(context.Background(), "cc", "ok", args...)
+	require.NoError(t, err)
+	args[0] = "changed"
+	_, err = recorder.QueryContext(context.Background(), "cc", "ok")
+	require.NoError(t, err)
+	_, err = recorder.QueryContext(context.Background(), "cc", "unknown")
+	require.ErrorIs(t, err, ErrUnknownMethod)
+
+	tests := []struct {
+		fcn      string
+		requests []string
+	}{
+		{fcn: "ok", requests: []string{RequestInvoke, RequestQuery}},
+		{fcn: "unknown", requests: []string{RequestQuery}},
+		{fcn: "other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fcn, func(t *testing.T) {
+			calls := recorder.CallsOf(tt.fcn)
+			require.Len(t, calls, len(tt.requests))
+			for i, call := range calls {
+				require.Equal(t, tt.requests[i], call.RequestType)
+				require.Equal(t, "cc", call.ChaincodeID)
+			}
+		})
+	}
+
+	calls := recorder.Calls()
+	require.Len(t, calls, 3)
+	require.Equal(t, []string{"a"}, calls[0].Args, "args are copied")
+	require.Equal(t, "cc-1", calls[0].Response.TransactionID)
+	require.ErrorIs(t, calls[2].Err, ErrUnknownMethod)
+
+	recorder.Reset()
+	require.Empty(t, recorder.Calls())
+}
@@ -0,0 +1,93 @@
+package fake
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	utils "github.com/anoideaopen/testnet-util"
+)
+
+// Request types of recorded calls
+const (
+	RequestInvoke = "invoke"
+	RequestQuery  = "query"
+)
+
+// Call struct for call recorded by Recorder
+type Call struct {
+	RequestType string
+	ChaincodeID string
+	Fcn         string
+	Args        []string
+	Response    *utils.Response
+	Err         error
+	Duration    time.Duration
+}
+
+// Recorder - utils.ChaincodeClient passing calls to next client and recording them for assertions in tests
+type Recorder struct {
+	next utils.ChaincodeClient
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ utils.ChaincodeClient = (*Recorder)(nil)
+
+// NewRecorder - create new instance of Recorder, next is HlfProxyService or Client
+func NewRecorder(next utils.ChaincodeClient) *Recorder {
+	return &Recorder{next: next}
+}
+
+// InvokeContext - pass invoke to next client and record it
+func (r *Recorder) InvokeContext(ctx context.Context, chaincodeID string, fcn string, args ...string) (*utils.Response, error) {
+	start := time.Now()
+	resp, err := r.next.InvokeContext(ctx, chaincodeID, fcn, args...)
+	r.record(Call{RequestInvoke, chaincodeID, fcn, args, resp, err, time.Since(start)})
+	return resp, err
+}
+
+// QueryContext - pass query to next client and record it
+func (r *Recorder) QueryContext(ctx context.Context, chaincodeID string, fcn string, args ...string) (*utils.Response, error) {
+	start := time.Now()
+	resp, err := r.next.QueryContext(ctx, chaincodeID, fcn, args...)
+	r.record(Call{RequestQuery, chaincodeID, fcn, args, resp, err, time.Since(start)})
+	return resp, err
+}
+
+// Calls returns recorded calls in order they were made
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	calls := make([]Call, len(r.calls))
+	copy(calls, r.calls)
+	return calls
+}
+
+// CallsOf returns recorded calls of method fcn
+func (r *Recorder) CallsOf(fcn string) []Call {
+	var calls []Call
+	for _, call := range r.Calls() {
+		if call.Fcn == fcn {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset removes recorded calls
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+func (r *Recorder) record(call Call) {
+	call.Args = append([]string{}, call.Args...)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
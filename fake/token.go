@@ -0,0 +1,204 @@
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	utils "github.com/anoideaopen/testnet-util"
+)
+
+// Positions in arguments signed by utils.Sign: request id, chaincode, channel, method arguments, nonce, public key, signature
+const (
+	signedArgsPos = 3
+	signedTailLen = 3
+)
+
+var (
+	// ErrInsufficientFunds - balance of sender is less than amount
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrUserNotFound - public key is not added to acl
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// Token - in-memory fungible token with methods emit, transfer, balanceOf and allowedBalanceOf.
+// Signatures are not verified, signer is taken from public key of signed arguments
+type Token struct {
+	mu       sync.Mutex
+	balances map[string]*big.Int
+	allowed  map[string]map[string]*big.Int
+}
+
+// NewToken - create new instance of Token with empty balances
+func NewToken() *Token {
+	return &Token{
+		balances: make(map[string]*big.Int),
+		allowed:  make(map[string]map[string]*big.Int),
+	}
+}
+
+// Register - register methods of token in client for chaincode chaincodeID
+func (t *Token) Register(client *Client, chaincodeID string) *Client {
+	return client.
+		Handle(chaincodeID, "emit", t.emit).
+		Handle(chaincodeID, "transfer", t.transfer).
+		Handle(chaincodeID, "balanceOf", t.balanceOf).
+		Handle(chaincodeID, "allowedBalanceOf", t.allowedBalanceOf)
+}
+
+// SetAllowedBalance sets allowed balance of token of address
+func (t *Token) SetAllowedBalance(address string, token string, amount *big.Int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.allowed[address] == nil {
+		t.allowed[address] = make(map[string]*big.Int)
+	}
+	t.allowed[address][token] = new(big.Int).Set(amount)
+}
+
+// emit - arguments address, amount
+func (t *Token) emit(_ context.Context, args []string) ([]byte, error) {
+	_, params, err := signedParams(args, 2) //nolint:gomnd
+	if err != nil {
+		return nil, err
+	}
+	amount, err := parseAmount(params[1])
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.balance(params[0]).Add(t.balance(params[0]), amount)
+	return nil, nil
+}
+
+// transfer - arguments address to, amount, ref
+func (t *Token) transfer(_ context.Context, args []string) ([]byte, error) {
+	from, params, err := signedParams(args, 3) //nolint:gomnd
+	if err != nil {
+		return nil, err
+	}
+	amount, err := parseAmount(params[1])
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.balance(from).Cmp(amount) < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInsufficientFunds, from)
+	}
+	t.balance(from).Sub(t.balance(from), amount)
+	t.balance(params[0]).Add(t.balance(params[0]), amount)
+	return nil, nil
+}
+
+// balanceOf - arguments address
+func (t *Token) balanceOf(_ context.Context, args []string) ([]byte, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("balanceOf: expected 1 argument, got %d", len(args))
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return json.Marshal(t.balance(args[0]).String())
+}
+
+// allowedBalanceOf - arguments address, token
+func (t *Token) allowedBalanceOf(_ context.Context, args []string) ([]byte, error) {
+	if len(args) != 2 { //nolint:gomnd
+		return nil, fmt.Errorf("allowedBalanceOf: expected 2 arguments, got %d", len(args))
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	amount, ok := t.allowed[args[0]][args[1]]
+	if !ok {
+		amount = new(big.Int)
+	}
+	return json.Marshal(amount.String())
+}
+
+func (t *Token) balance(address string) *big.Int {
+	if t.balances[address] == nil {
+		t.balances[address] = new(big.Int)
+	}
+	return t.balances[address]
+}
+
+// ACL - in-memory acl chaincode with methods addUser and checkKeys
+type ACL struct {
+	mu    sync.Mutex
+	users map[string]string
+}
+
+// NewACL - create new instance of ACL without users
+func NewACL() *ACL {
+	return &ACL{users: make(map[string]string)}
+}
+
+// Register - register methods of acl in client for chaincode chaincodeID
+func (a *ACL) Register(client *Client, chaincodeID string) *Client {
+	return client.
+		Handle(chaincodeID, "addUser", a.addUser).
+		Handle(chaincodeID, "checkKeys", a.checkKeys)
+}
+
+// addUser - arguments public key, kyc hash, user id, is industrial
+func (a *ACL) addUser(_ context.Context, args []string) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, errors.New("addUser: public key is required")
+	}
+	address, err := utils.GetAddressByPublicKeyBase58(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.users[args[0]]; ok {
+		return nil, fmt.Errorf("the user with public key %s already exists", args[0])
+	}
+	a.users[args[0]] = address
+	return nil, nil
+}
+
+// checkKeys - arguments public key
+func (a *ACL) checkKeys(_ context.Context, args []string) ([]byte, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("checkKeys: expected 1 argument, got %d", len(args))
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	address, ok := a.users[args[0]]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, args[0])
+	}
+	return json.Marshal(map[string]string{"address": address})
+}
+
+// signedParams returns address of signer and n method arguments of arguments signed by utils.Sign
+func signedParams(args []string, n int) (string, []string, error) {
+	if len(args) != signedArgsPos+n+signedTailLen {
+		return "", nil, fmt.Errorf("expected %d signed arguments, got %d", n, len(args)-signedArgsPos-signedTailLen)
+	}
+	address, err := utils.GetAddressByPublicKeyBase58(args[len(args)-2])
+	if err != nil {
+		return "", nil, err
+	}
+	return address, args[signedArgsPos : signedArgsPos+n], nil
+}
+
+func parseAmount(s string) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(s, 10) //nolint:gomnd
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	return amount, nil
+}
@@ -0,0 +1,119 @@
+package fake
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	utils "github.com/anoideaopen/testnet-util"
+	"github.com/stretchr/testify/require"
+)
+
+func testUser(t *testing.T, name string) utils.User {
+	t.Helper()
+	privateKey, _, err := utils.DerivePrivateAndPublicKey([]byte("fake"), name)
+	require.NoError(t, err)
+	user, err := utils.NewUser(privateKey)
+	require.NoError(t, err)
+	return user
+}
+
+func signed(t *testing.T, user utils.User, fcn string, args ...string) []string {
+	t.Helper()
+	signedArgs, err := utils.Sign(user.UserEd25519PrivateKey, user.UserEd25519PublicKey, "fiat", "fiat", fcn, args)
+	require.NoError(t, err)
+	return signedArgs
+}
+
+func TestToken(t *testing.T) {
+	token := NewToken()
+	client := token.Register(NewClient(), "fiat")
+	issuer, from, to := testUser(t, "issuer"), testUser(t, "from"), testUser(t, "to")
+	token.SetAllowedBalance(from.UserAddressBase58Check, "CC", big.NewInt(7))
+
+	tests := []struct {
+		name   string
+		invoke bool
+		fcn    string
+		args   []string
+		result string
+		err    error
+		failed bool
+	}{
+		{name: "emit", invoke: true, fcn: "emit", args: signed(t, issuer, "emit", from.UserAddressBase58Check, "10")},
+		{name: "transfer", invoke: true, fcn: "transfer", args: signed(t, from, "transfer", to.UserAddressBase58Check, "4", "ref")},
+		{name: "balance of sender", fcn: "balanceOf", args: []string{from.UserAddressBase58Check}, result: `"6"`},
+		{name: "balance of receiver", fcn: "balanceOf", args: []string{to.UserAddressBase58Check}, result: `"4"`},
+		{
+			name: "insufficient funds", invoke: true, fcn: "transfer",
+			args: signed(t, to, "transfer", from.UserAddressBase58Check, "5", "ref"), err: ErrInsufficientFunds,
+		},
+		{name: "balance is kept on error", fcn: "balanceOf", args: []string{to.UserAddressBase58Check}, result: `"4"`},
+		{name: "allowed balance", fcn: "allowedBalanceOf", args: []string{from.UserAddressBase58Check, "CC"}, result: `"7"`},
+		{name: "allowed balance not set", fcn: "allowedBalanceOf", args: []string{to.UserAddressBase58Check, "CC"}, result: `"0"`},
+		{name: "invalid amount", invoke: true, fcn: "emit", args: signed(t, issuer, "emit", from.UserAddressBase58Check, "-1"), failed: true},
+		{name: "not signed", invoke: true, fcn: "emit", args: []string{from.UserAddressBase58Check, "1"}, failed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call := client.QueryContext
+			if tt.invoke {
+				call = client.InvokeContext
+			}
+			resp, err := call(context.Background(), "fiat", tt.fcn, tt.args...)
+			switch {
+			case tt.err != nil:
+				require.ErrorIs(t, err, tt.err)
+			case tt.failed:
+				require.Error(t, err)
+			default:
+				require.NoError(t, err)
+				if tt.result != "" {
+					require.Equal(t, tt.result, string(resp.Payload))
+				}
+			}
+		})
+	}
+}
+
+func TestACL(t *testing.T) {
+	client := NewACL().Register(NewClient(), "acl")
+	user := testUser(t, "user")
+	addUser := []string{user.UserPublicKeyBase58, "test", "testuser", "true"}
+	checkKeys := []string{user.UserPublicKeyBase58}
+
+	tests := []struct {
+		name   string
+		invoke bool
+		fcn    string
+		args   []string
+		err    error
+		failed bool
+	}{
+		{name: "not added", fcn: "checkKeys", args: checkKeys, err: ErrUserNotFound},
+		{name: "add", invoke: true, fcn: "addUser", args: addUser},
+		{name: "added", fcn: "checkKeys", args: checkKeys},
+		{name: "add again", invoke: true, fcn: "addUser", args: addUser, failed: true},
+		{name: "invalid public key", invoke: true, fcn: "addUser", args: []string{"key"}, failed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call := client.QueryContext
+			if tt.invoke {
+				call = client.InvokeContext
+			}
+			resp, err := call(context.Background(), "acl", tt.fcn, tt.args...)
+			switch {
+			case tt.err != nil:
+				require.ErrorIs(t, err, tt.err)
+			case tt.failed:
+				require.Error(t, err)
+			default:
+				require.NoError(t, err)
+				if tt.fcn == "checkKeys" {
+					require.JSONEq(t, `{"address":"`+user.UserAddressBase58Check+`"}`, string(resp.Payload))
+				}
+			}
+		})
+	}
+}
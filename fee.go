@@ -0,0 +1,220 @@
+package utils
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+// TokenFee struct for transfer fee of token from metadata
+// Address - address fee is transferred to
+// Currency - ticker of token fee is paid in, token balance is charged if it is symbol of token and allowed balance otherwise
+// Fee - part of amount multiplied by 10^RateDecimals, example 1000000 is 1%
+// Floor, Cap - min and max fee, no max if zero
+type TokenFee struct {
+	Address  string   `json:"address"`
+	Currency string   `json:"currency"`
+	Fee      *big.Int `json:"fee"`
+	Floor    *big.Int `json:"floor"`
+	Cap      *big.Int `json:"cap"`
+}
+
+// Calc returns fee of transfer of amount of tokens the way chaincode calculates it:
+// amount * Fee / 10^RateDecimals rounded down, raised to Floor and lowered to Cap
+func (f *TokenFee) Calc(amount *big.Int) *big.Int {
+	if f == nil || f.Fee == nil {
+		return new(big.Int)
+	}
+
+	fee := new(big.Int).Mul(amount, f.Fee)
+	fee.Div(fee, new(big.Int).Exp(big.NewInt(10), big.NewInt(RateDecimals), nil)) //nolint:gomnd
+	if f.Floor != nil && fee.Cmp(f.Floor) < 0 {
+		fee.Set(f.Floor)
+	}
+	if f.Cap != nil && f.Cap.Sign() > 0 && fee.Cmp(f.Cap) > 0 {
+		fee.Set(f.Cap)
+	}
+	return fee
+}
+
+// GetFee returns transfer fee of token in channel from metadata, nil if fee is not set
+func GetFee(hlfProxy ChaincodeClient, channel string) (*TokenFee, error) {
+	metadata, err := GetMetadata(hlfProxy, channel)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.Fee, nil
+}
+
+// SetFee signs by fee setter and invokes setting transfer fee paid in currency.
+// fee - part of amount multiplied by 10^RateDecimals, floor and feeCap - limits of fee, feeCap "0" means no limit
+func SetFee(hlfProxy ChaincodeClient, feeSetter Issuer, channel string, chaincode string, currency string, fee string, floor string, feeCap string) (*Response, error) {
+	return invokeByIssuer(hlfProxy, feeSetter, channel, chaincode, "setFee", currency, fee, floor, feeCap)
+}
+
+// SetFeeAddress signs by fee address setter and invokes setting address fee is transferred to
+func SetFeeAddress(hlfProxy ChaincodeClient, feeAddressSetter Issuer, channel string, chaincode string, feeAddressBase58Check string) (*Response, error) {
+	return invokeByIssuer(hlfProxy, feeAddressSetter, channel, chaincode, "setFeeAddress", feeAddressBase58Check)
+}
+
+// SetFeeAndCheck sets transfer fee with limits and checks that metadata contains it
+func SetFeeAndCheck(
+	t provider.T,
+	hlfProxy ChaincodeClient,
+	feeSetter Issuer,
+	channel string,
+	chaincode string,
+	currency string,
+	fee string,
+	floor string,
+	feeCap string,
+) {
+	t.WithNewStep("Set fee "+fee+" in "+currency+" with floor "+floor+" and cap "+feeCap, func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		_, err := SetFee(hlfProxy, feeSetter, channel, chaincode, currency, fee, floor, feeCap)
+		sCtx.Require().NoError(err)
+		time.Sleep(BatchTransactionTimeout)
+	})
+
+	t.WithNewStep("Checking that fee equal "+fee+" in "+currency+" with floor "+floor+" and cap "+feeCap, func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		tokenFee, err := GetFee(hlfProxy, channel)
+		sCtx.Require().NoError(err)
+		sCtx.Require().NotNil(tokenFee)
+		sCtx.Require().Equal(currency, tokenFee.Currency)
+		sCtx.Require().Equal(fee, tokenFee.Fee.String())
+		sCtx.Require().Equal(floor, tokenFee.Floor.String())
+		sCtx.Require().Equal(feeCap, tokenFee.Cap.String())
+	})
+}
+
+// SetFeeAddressAndCheck sets address fee is transferred to and checks that metadata contains it
+func SetFeeAddressAndCheck(t provider.T, hlfProxy ChaincodeClient, feeAddressSetter Issuer, channel string, chaincode string, feeAddressBase58Check string) {
+	t.WithNewStep("Set fee address "+feeAddressBase58Check, func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		_, err := SetFeeAddress(hlfProxy, feeAddressSetter, channel, chaincode, feeAddressBase58Check)
+		sCtx.Require().NoError(err)
+		time.Sleep(BatchTransactionTimeout)
+	})
+
+	t.WithNewStep("Checking that fee address equal "+feeAddressBase58Check, func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		tokenFee, err := GetFee(hlfProxy, channel)
+		sCtx.Require().NoError(err)
+		sCtx.Require().NotNil(tokenFee)
+		sCtx.Require().Equal(feeAddressBase58Check, tokenFee.Address)
+	})
+}
+
+// TransferWithFeeCheckBalancesAndGetResponse transfers amount of tokens from userFrom to userToAddress and checks together
+// that sender is debited by amount and fee, receiver is credited by amount and fee address is credited by fee calculated by Calc
+func TransferWithFeeCheckBalancesAndGetResponse(
+	t provider.T,
+	hlfProxy ChaincodeClient,
+	userFrom User,
+	userToAddress string,
+	channel string,
+	chaincode string,
+	amount string,
+) *Response {
+	var res *Response
+	from := userFrom.UserAddressBase58Check
+	t.WithNewStep("Transfer "+amount+" token with fee from user "+from+" to user "+userToAddress, func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		metadata, err := GetMetadata(hlfProxy, channel)
+		sCtx.Require().NoError(err)
+		value := parseAmount(sCtx, amount)
+		fee := metadata.Fee.Calc(value)
+		sCtx.WithNewParameters("fee", fee.String())
+
+		// fee is paid from token balance if it is in token currency and from allowed balance otherwise
+		feeToken := ""
+		if metadata.Fee != nil && metadata.Fee.Currency != metadata.Symbol {
+			feeToken = metadata.Fee.Currency
+		}
+		feeAddress := ""
+		if metadata.Fee != nil && fee.Sign() > 0 {
+			feeAddress = metadata.Fee.Address
+		}
+
+		before, err := getTransferBalances(hlfProxy, channel, from, userToAddress, feeAddress, feeToken)
+		sCtx.Require().NoError(err)
+
+		res, err = Transfer(hlfProxy, userFrom, userToAddress, channel, chaincode, amount, "ref transfer")
+		sCtx.Require().NoError(err)
+		time.Sleep(BatchTransactionTimeout)
+
+		expected := before.copy()
+		expected.from.Sub(expected.from, value)
+		expected.to.Add(expected.to, value)
+		if feeToken == "" {
+			expected.from.Sub(expected.from, fee)
+		} else {
+			expected.fromFee.Sub(expected.fromFee, fee)
+		}
+		if feeAddress != "" {
+			expected.feeAddress.Add(expected.feeAddress, fee)
+		}
+
+		after, err := getTransferBalances(hlfProxy, channel, from, userToAddress, feeAddress, feeToken)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal(expected.from.String(), after.from.String(), "sender balance")
+		sCtx.Require().Equal(expected.to.String(), after.to.String(), "receiver balance")
+		if feeToken != "" {
+			sCtx.Require().Equal(expected.fromFee.String(), after.fromFee.String(), "sender allowed balance of %s", feeToken)
+		}
+		if feeAddress != "" {
+			sCtx.Require().Equal(expected.feeAddress.String(), after.feeAddress.String(), "fee address balance")
+		}
+	})
+	return res
+}
+
+// transferBalances struct for balances changed by transfer with fee
+// fromFee - allowed balance of sender in fee currency, feeAddress - balance of fee address in fee currency
+type transferBalances struct {
+	from       *big.Int
+	to         *big.Int
+	fromFee    *big.Int
+	feeAddress *big.Int
+}
+
+// copy returns transferBalances with copies of balances, so they can be changed without changing b
+func (b transferBalances) copy() transferBalances {
+	return transferBalances{
+		from:       new(big.Int).Set(b.from),
+		to:         new(big.Int).Set(b.to),
+		fromFee:    new(big.Int).Set(b.fromFee),
+		feeAddress: new(big.Int).Set(b.feeAddress),
+	}
+}
+
+func getTransferBalances(hlfProxy ChaincodeClient, channel string, from string, to string, feeAddress string, feeToken string) (transferBalances, error) {
+	var (
+		b   transferBalances
+		err error
+	)
+	if b.from, err = QueryAmount(hlfProxy, channel, "balanceOf", from); err != nil {
+		return b, err
+	}
+	if b.to, err = QueryAmount(hlfProxy, channel, "balanceOf", to); err != nil {
+		return b, err
+	}
+
+	b.fromFee, b.feeAddress = new(big.Int), new(big.Int)
+	if feeToken != "" {
+		if b.fromFee, err = QueryAmount(hlfProxy, channel, "allowedBalanceOf", from, feeToken); err != nil {
+			return b, err
+		}
+	}
+	if feeAddress == "" {
+		return b, nil
+	}
+	if feeToken == "" {
+		b.feeAddress, err = QueryAmount(hlfProxy, channel, "balanceOf", feeAddress)
+	} else {
+		b.feeAddress, err = QueryAmount(hlfProxy, channel, "allowedBalanceOf", feeAddress, feeToken)
+	}
+	return b, err
+}
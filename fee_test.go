@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenFeeCalc(t *testing.T) {
+	// onePercent - fee 1% multiplied by 10^RateDecimals
+	onePercent := big.NewInt(1000000)
+
+	tests := []struct {
+		name   string
+		fee    *TokenFee
+		amount int64
+		want   string
+	}{
+		{name: "nil fee", amount: 1000, want: "0"},
+		{name: "fee is not set", fee: &TokenFee{Currency: "FIAT"}, amount: 1000, want: "0"},
+		{name: "part of amount", fee: &TokenFee{Fee: onePercent}, amount: 1000, want: "10"},
+		{name: "rounding down", fee: &TokenFee{Fee: onePercent}, amount: 199, want: "1"},
+		{name: "floor applied", fee: &TokenFee{Fee: onePercent, Floor: big.NewInt(5)}, amount: 100, want: "5"},
+		{name: "floor not applied", fee: &TokenFee{Fee: onePercent, Floor: big.NewInt(5)}, amount: 1000, want: "10"},
+		{name: "cap applied", fee: &TokenFee{Fee: onePercent, Cap: big.NewInt(3)}, amount: 1000, want: "3"},
+		{name: "zero cap means no cap", fee: &TokenFee{Fee: onePercent, Cap: big.NewInt(0)}, amount: 1000, want: "10"},
+		{
+			name:   "floor and cap",
+			fee:    &TokenFee{Fee: onePercent, Floor: big.NewInt(2), Cap: big.NewInt(3)},
+			amount: 100,
+			want:   "2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount := big.NewInt(tt.amount)
+			require.Equal(t, tt.want, tt.fee.Calc(amount).String())
+			require.Equal(t, tt.amount, amount.Int64(), "amount is not changed")
+		})
+	}
+}
+
+func TestTransferBalancesCopy(t *testing.T) {
+	before := transferBalances{from: big.NewInt(10), to: big.NewInt(20), fromFee: big.NewInt(30), feeAddress: big.NewInt(40)}
+
+	expected := before.copy()
+	expected.from.Sub(expected.from, big.NewInt(1))
+	expected.to.Add(expected.to, big.NewInt(1))
+	expected.fromFee.Sub(expected.fromFee, big.NewInt(1))
+	expected.feeAddress.Add(expected.feeAddress, big.NewInt(1))
+
+	require.Equal(t, "10 20 30 40", before.from.String()+" "+before.to.String()+" "+before.fromFee.String()+" "+before.feeAddress.String())
+}
@@ -0,0 +1,282 @@
+// Package gateway contains utils.ChaincodeClient sending requests to Fabric Gateway of peer over gRPC
+// on behalf of MSP identity, alternative to hlf proxy, and fake gateway server to check it without stand
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	utils "github.com/anoideaopen/testnet-util"
+	gatewaypb "github.com/hyperledger/fabric-protos-go-apiv2/gateway"
+	"github.com/hyperledger/fabric-protos-go-apiv2/peer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// Endpoint - host and port of peer with Fabric Gateway, example localhost:7051
+	Endpoint = "GATEWAY_ENDPOINT"
+	// MSPID - id of MSP of identity, example Org1MSP
+	MSPID = "GATEWAY_MSP_ID"
+	// CertPath - path to x509 certificate of identity in PEM
+	CertPath = "GATEWAY_CERT_PATH"
+	// KeyPath - path to private key of identity in PEM
+	KeyPath = "GATEWAY_KEY_PATH"
+	// TLSCACertPath - path to CA certificate of peer TLS in PEM
+	TLSCACertPath = "GATEWAY_TLS_CA_CERT_PATH"
+	// TLSServerName - name of peer in its TLS certificate if it differs from host of endpoint, example peer0.org1.example.com
+	TLSServerName = "GATEWAY_TLS_SERVER_NAME"
+	// Insecure - "true" to connect without TLS if TLSCACertPath is not set
+	Insecure = "GATEWAY_INSECURE"
+)
+
+const (
+	chaincodeStatusOK    = 200
+	chaincodeStatusError = utils.ChaincodeStatusErrorThreshold
+)
+
+// Client - utils.ChaincodeClient sending requests to Fabric Gateway.
+// Invoke is endorsed, submitted to orderer and waited for commit, response has the same
+// transaction id, block number and validation code as response of hlf proxy
+type Client struct {
+	conn          *grpc.ClientConn
+	gateway       gatewaypb.GatewayClient
+	id            Identity
+	chaincodes    map[string]string
+	endorsingOrgs []string
+}
+
+var _ utils.ChaincodeClient = (*Client)(nil)
+
+// Option - option of Client
+type Option func(c *Client)
+
+// WithChaincode - set name of chaincode deployed on channel, by default chaincode has the same name as channel
+func WithChaincode(channel string, chaincode string) Option {
+	return func(c *Client) {
+		c.chaincodes[channel] = chaincode
+	}
+}
+
+// WithEndorsingOrganizations - set MSP ids of organizations to endorse invokes, by default gateway chooses them by endorsement policy
+func WithEndorsingOrganizations(mspIDs ...string) Option {
+	return func(c *Client) {
+		c.endorsingOrgs = mspIDs
+	}
+}
+
+// NewClient - create new instance of Client sending requests over conn on behalf of id, conn is closed by Close
+func NewClient(conn *grpc.ClientConn, id Identity, opts ...Option) *Client {
+	c := &Client{
+		conn:       conn,
+		gateway:    gatewaypb.NewGatewayClient(conn),
+		id:         id,
+		chaincodes: make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Dial - connect to Fabric Gateway on target with transport credentials creds and create new instance of Client,
+// creds are TLS credentials or insecure.NewCredentials() to connect without TLS
+func Dial(
+	target string,
+	id Identity,
+	creds credentials.TransportCredentials,
+	dialOpts []grpc.DialOption,
+	opts ...Option,
+) (*Client, error) {
+	if creds == nil {
+		return nil, errors.New("transport credentials are not set")
+	}
+	dialOpts = append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, dialOpts...)
+	conn, err := grpc.Dial(target, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("dial gateway %s: %w", target, err)
+	}
+	return NewClient(conn, id, opts...), nil
+}
+
+// DialFromEnv - connect to Fabric Gateway with endpoint, identity and TLS set by env
+// GATEWAY_ENDPOINT, GATEWAY_MSP_ID, GATEWAY_CERT_PATH, GATEWAY_KEY_PATH, GATEWAY_TLS_CA_CERT_PATH and GATEWAY_TLS_SERVER_NAME,
+// connection without TLS is made only if GATEWAY_INSECURE is true
+func DialFromEnv(opts ...Option) (*Client, error) {
+	endpoint := os.Getenv(Endpoint)
+	if endpoint == "" {
+		return nil, errors.New("env " + Endpoint + " is not set")
+	}
+	id, err := LoadIdentity(os.Getenv(MSPID), os.Getenv(CertPath), os.Getenv(KeyPath))
+	if err != nil {
+		return nil, err
+	}
+
+	var creds credentials.TransportCredentials
+	switch caPath := os.Getenv(TLSCACertPath); {
+	case caPath != "":
+		if creds, err = tlsCredentials(caPath, os.Getenv(TLSServerName)); err != nil {
+			return nil, err
+		}
+	case os.Getenv(Insecure) == "true":
+		creds = insecure.NewCredentials()
+	default:
+		return nil, errors.New("env " + TLSCACertPath + " is not set, set " + Insecure + "=true to connect without TLS")
+	}
+	return Dial(endpoint, id, creds, nil, opts...)
+}
+
+func tlsCredentials(caPath string, serverName string) (credentials.TransportCredentials, error) {
+	ca, err := os.ReadFile(caPath)
+	if err != nil {
+		return nil, fmt.Errorf("read tls ca certificate: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("tls ca certificate is not in PEM")
+	}
+	return credentials.NewTLS(&tls.Config{
+		RootCAs:    pool,
+		ServerName: serverName,
+		MinVersion: tls.VersionTLS12,
+	}), nil
+}
+
+// Close - close connection to gateway
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Invoke - send invoke request to channel chaincodeID through gateway and wait for commit, see InvokeContext
+func (c *Client) Invoke(chaincodeID string, fcn string, args ...string) (*utils.Response, error) {
+	return c.InvokeContext(context.Background(), chaincodeID, fcn, args...)
+}
+
+// Query - send query request to channel chaincodeID through gateway, see QueryContext
+func (c *Client) Query(chaincodeID string, fcn string, args ...string) (*utils.Response, error) {
+	return c.QueryContext(context.Background(), chaincodeID, fcn, args...)
+}
+
+// InvokeContext - endorse transaction, submit it and wait for its commit, returns response together with
+// utils.ErrTxNotValid if transaction was not committed as VALID
+func (c *Client) InvokeContext(ctx context.Context, chaincodeID string, fcn string, args ...string) (*utils.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.InvokeTimeout)
+	defer cancel()
+
+	txID, signed, err := c.proposal(chaincodeID, fcn, args)
+	if err != nil {
+		return nil, err
+	}
+
+	endorseResponse, err := c.gateway.Endorse(ctx, &gatewaypb.EndorseRequest{
+		TransactionId:          txID,
+		ChannelId:              chaincodeID,
+		ProposedTransaction:    signed,
+		EndorsingOrganizations: c.endorsingOrgs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("endorse %s: %w", txID, err)
+	}
+	response, err := transactionResponse(endorseResponse.GetPreparedTransaction())
+	if err != nil {
+		return nil, err
+	}
+	envelope, err := signEnvelope(c.id, endorseResponse.GetPreparedTransaction())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = c.gateway.Submit(ctx, &gatewaypb.SubmitRequest{
+		TransactionId:       txID,
+		ChannelId:           chaincodeID,
+		PreparedTransaction: envelope,
+	}); err != nil {
+		return nil, fmt.Errorf("submit %s: %w", txID, err)
+	}
+
+	creator, err := c.id.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	statusRequest, err := proto.Marshal(&gatewaypb.CommitStatusRequest{
+		TransactionId: txID,
+		ChannelId:     chaincodeID,
+		Identity:      creator,
+	})
+	if err != nil {
+		return nil, err
+	}
+	signature, err := c.id.Sign(statusRequest)
+	if err != nil {
+		return nil, err
+	}
+	status, err := c.gateway.CommitStatus(ctx, &gatewaypb.SignedCommitStatusRequest{
+		Request:   statusRequest,
+		Signature: signature,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("commit status %s: %w", txID, err)
+	}
+
+	resp := &utils.Response{
+		BlockNumber:      int64(status.GetBlockNumber()),
+		ChaincodeStatus:  int64(response.GetStatus()),
+		Payload:          response.GetPayload(),
+		TransactionID:    txID,
+		TxValidationCode: int64(status.GetResult()),
+	}
+	return resp, resp.CheckValid()
+}
+
+// QueryContext - evaluate transaction on peer without submit
+func (c *Client) QueryContext(ctx context.Context, chaincodeID string, fcn string, args ...string) (*utils.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.QueryTimeout)
+	defer cancel()
+
+	txID, signed, err := c.proposal(chaincodeID, fcn, args)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluateResponse, err := c.gateway.Evaluate(ctx, &gatewaypb.EvaluateRequest{
+		TransactionId:       txID,
+		ChannelId:           chaincodeID,
+		ProposedTransaction: signed,
+		TargetOrganizations: c.endorsingOrgs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("evaluate %s: %w", fcn, err)
+	}
+	response := evaluateResponse.GetResult()
+	if response.GetStatus() >= chaincodeStatusError {
+		return nil, fmt.Errorf("evaluate %s: chaincode status %d: %s", fcn, response.GetStatus(), response.GetMessage())
+	}
+
+	return &utils.Response{
+		ChaincodeStatus: int64(response.GetStatus()),
+		Payload:         response.GetPayload(),
+	}, nil
+}
+
+// proposal returns id of transaction and signed proposal of method fcn of chaincode on channel
+func (c *Client) proposal(channel string, fcn string, args []string) (string, *peer.SignedProposal, error) {
+	chaincode, ok := c.chaincodes[channel]
+	if !ok {
+		chaincode = channel
+	}
+	txID, proposal, err := newProposal(c.id, channel, chaincode, fcn, args)
+	if err != nil {
+		return "", nil, err
+	}
+	signed, err := signedProposal(c.id, proposal)
+	if err != nil {
+		return "", nil, err
+	}
+	return txID, signed, nil
+}
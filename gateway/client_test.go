@@ -0,0 +1,101 @@
+package gateway_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	utils "github.com/anoideaopen/testnet-util"
+	"github.com/anoideaopen/testnet-util/fake"
+	"github.com/anoideaopen/testnet-util/gateway"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// invalidBackend commits every invoke with MVCC_READ_CONFLICT as hlf proxy returns it
+type invalidBackend struct{}
+
+func (invalidBackend) InvokeContext(_ context.Context, chaincodeID string, _ string, _ ...string) (*utils.Response, error) {
+	resp := &utils.Response{TransactionID: chaincodeID, BlockNumber: 1, TxValidationCode: int64(utils.TxMvccReadConflict)}
+	return resp, resp.CheckValid()
+}
+
+func (invalidBackend) QueryContext(context.Context, string, string, ...string) (*utils.Response, error) {
+	return &utils.Response{}, nil
+}
+
+func newGatewayClient(t *testing.T, backend utils.ChaincodeClient) *gateway.Client {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	id := gateway.Identity{MSPID: "Org1MSP", Certificate: []byte("certificate"), PrivateKey: key}
+
+	server := gateway.NewFakeServer(backend)
+	require.NoError(t, server.Start("127.0.0.1:0"))
+	t.Cleanup(server.Stop)
+
+	client, err := gateway.Dial(server.Addr(), id, insecure.NewCredentials(), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestClient(t *testing.T) {
+	var invoked int
+	backend := fake.NewClient().
+		Handle("cc", "put", func(_ context.Context, args []string) ([]byte, error) {
+			invoked++
+			return []byte("put " + args[0]), nil
+		}).
+		Handle("cc", "get", func(_ context.Context, args []string) ([]byte, error) { return []byte(args[0]), nil })
+	client := newGatewayClient(t, backend)
+
+	resp, err := client.InvokeContext(context.Background(), "cc", "put", "a")
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.TransactionID)
+	require.Equal(t, int64(1), resp.BlockNumber)
+	require.Equal(t, utils.TxValid, resp.ValidationCode())
+	require.Equal(t, "put a", string(resp.Payload), "invoke payload is endorsed")
+	require.Equal(t, 1, invoked, "invoke is executed once")
+
+	resp, err = client.QueryContext(context.Background(), "cc", "get", "a")
+	require.NoError(t, err)
+	require.Equal(t, "a", string(resp.Payload))
+
+	tests := []struct {
+		name   string
+		invoke bool
+	}{
+		{name: "invoke", invoke: true},
+		{name: "query"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" unknown method", func(t *testing.T) {
+			call := client.QueryContext
+			if tt.invoke {
+				call = client.InvokeContext
+			}
+			_, err := call(context.Background(), "cc", "unknown")
+			require.Error(t, err)
+			require.Equal(t, codes.Aborted, status.Code(err))
+		})
+	}
+}
+
+func TestClientInvalidTx(t *testing.T) {
+	client := newGatewayClient(t, invalidBackend{})
+
+	resp, err := client.InvokeContext(context.Background(), "cc", "put")
+	require.ErrorIs(t, err, utils.ErrTxNotValid)
+	require.Equal(t, utils.TxMvccReadConflict, resp.ValidationCode())
+	require.Equal(t, int64(1), resp.BlockNumber)
+}
+
+func TestDialWithoutCredentials(t *testing.T) {
+	_, err := gateway.Dial("127.0.0.1:0", gateway.Identity{}, nil, nil)
+	require.Error(t, err)
+}
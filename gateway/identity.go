@@ -0,0 +1,104 @@
+package gateway
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/hyperledger/fabric-protos-go-apiv2/msp"
+	"google.golang.org/protobuf/proto"
+)
+
+// Identity struct for MSP identity requests are signed with
+// Certificate - x509 certificate in PEM
+// PrivateKey - ecdsa or ed25519 private key of certificate
+type Identity struct {
+	MSPID       string
+	Certificate []byte
+	PrivateKey  crypto.Signer
+}
+
+// NewIdentity - create identity from certificate and private key in PEM, key is PKCS8, EC or ed25519
+func NewIdentity(mspID string, certificatePEM []byte, privateKeyPEM []byte) (Identity, error) {
+	if block, _ := pem.Decode(certificatePEM); block == nil {
+		return Identity{}, errors.New("certificate is not in PEM")
+	}
+
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return Identity{}, errors.New("private key is not in PEM")
+	}
+
+	var (
+		key any
+		err error
+	)
+	if block.Type == "EC PRIVATE KEY" {
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	} else {
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return Identity{}, fmt.Errorf("parse private key: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return Identity{}, fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	return Identity{
+		MSPID:       mspID,
+		Certificate: certificatePEM,
+		PrivateKey:  signer,
+	}, nil
+}
+
+// LoadIdentity - create identity from files with certificate and private key in PEM
+func LoadIdentity(mspID string, certificatePath string, privateKeyPath string) (Identity, error) {
+	certificate, err := os.ReadFile(certificatePath)
+	if err != nil {
+		return Identity{}, fmt.Errorf("read certificate: %w", err)
+	}
+	privateKey, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return Identity{}, fmt.Errorf("read private key: %w", err)
+	}
+	return NewIdentity(mspID, certificate, privateKey)
+}
+
+// Serialize returns identity as msp.SerializedIdentity used as creator of transactions
+func (id Identity) Serialize() ([]byte, error) {
+	return proto.Marshal(&msp.SerializedIdentity{Mspid: id.MSPID, IdBytes: id.Certificate})
+}
+
+// Sign returns signature of message the way fabric verifies it:
+// ecdsa signature of sha256 digest with low S in ASN.1 DER or ed25519 signature of message
+func (id Identity) Sign(msg []byte) ([]byte, error) {
+	switch key := id.PrivateKey.(type) {
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256(msg)
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		if err != nil {
+			return nil, err
+		}
+		halfOrder := new(big.Int).Rsh(key.Params().N, 1)
+		if s.Cmp(halfOrder) > 0 {
+			s.Sub(key.Params().N, s)
+		}
+		return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	case ed25519.PrivateKey:
+		return ed25519.Sign(key, msg), nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+}
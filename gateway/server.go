@@ -0,0 +1,179 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+
+	utils "github.com/anoideaopen/testnet-util"
+	gatewaypb "github.com/hyperledger/fabric-protos-go-apiv2/gateway"
+	"github.com/hyperledger/fabric-protos-go-apiv2/peer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// FakeServer - fake Fabric Gateway server to check Client and suites without stand,
+// proposals are executed by backend, for example by fake.Client, channel of proposal is used as chaincodeID of backend.
+// Signatures are not verified. Invoke is executed by backend on endorse as endorsing peer simulates it,
+// so prepared transaction has payload of backend response, the response is committed on submit
+// with its validation code and block number. Backend has no rollback, invoke endorsed but not submitted is still applied
+type FakeServer struct {
+	server *grpc.Server
+	lis    net.Listener
+}
+
+// fakeGateway - gateway service of FakeServer
+type fakeGateway struct {
+	gatewaypb.UnimplementedGatewayServer
+
+	backend utils.ChaincodeClient
+
+	mu        sync.Mutex
+	endorsed  map[string]*utils.Response
+	committed map[string]*utils.Response
+}
+
+// NewFakeServer - create new instance of FakeServer executing proposals by backend
+func NewFakeServer(backend utils.ChaincodeClient) *FakeServer {
+	s := &FakeServer{server: grpc.NewServer()}
+	gatewaypb.RegisterGatewayServer(s.server, &fakeGateway{
+		backend:   backend,
+		endorsed:  make(map[string]*utils.Response),
+		committed: make(map[string]*utils.Response),
+	})
+	return s
+}
+
+// Start - listen on address and serve in background, example 127.0.0.1:0 listens on random port, see Addr
+func (s *FakeServer) Start(address string) error {
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	s.lis = lis
+	go func() {
+		_ = s.server.Serve(lis)
+	}()
+	return nil
+}
+
+// Addr returns address server listens on
+func (s *FakeServer) Addr() string {
+	if s.lis == nil {
+		return ""
+	}
+	return s.lis.Addr().String()
+}
+
+// Stop - stop server and close connections
+func (s *FakeServer) Stop() {
+	s.server.Stop()
+}
+
+func (g *fakeGateway) Endorse(ctx context.Context, request *gatewaypb.EndorseRequest) (*gatewaypb.EndorseResponse, error) {
+	inv, err := parseRequest(request.GetProposedTransaction())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.backend.InvokeContext(ctx, inv.Channel, string(inv.Args[0]), stringArgs(inv.Args[1:])...)
+	if resp == nil {
+		return nil, chaincodeError(err)
+	}
+
+	prepared, err := preparedTransaction(request.GetProposedTransaction(), &peer.Response{
+		Status:  chaincodeStatusOK,
+		Payload: resp.Payload,
+	})
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	g.mu.Lock()
+	g.endorsed[inv.TxID] = resp
+	g.mu.Unlock()
+
+	return &gatewaypb.EndorseResponse{PreparedTransaction: prepared}, nil
+}
+
+func (g *fakeGateway) Submit(_ context.Context, request *gatewaypb.SubmitRequest) (*gatewaypb.SubmitResponse, error) {
+	if len(request.GetPreparedTransaction().GetSignature()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "transaction is not signed")
+	}
+
+	txID := request.GetTransactionId()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	resp, ok := g.endorsed[txID]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "transaction "+txID+" is not endorsed")
+	}
+	delete(g.endorsed, txID)
+	g.committed[txID] = resp
+	return &gatewaypb.SubmitResponse{}, nil
+}
+
+func (g *fakeGateway) CommitStatus(
+	_ context.Context,
+	signed *gatewaypb.SignedCommitStatusRequest,
+) (*gatewaypb.CommitStatusResponse, error) {
+	request := &gatewaypb.CommitStatusRequest{}
+	if err := proto.Unmarshal(signed.GetRequest(), request); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	txID := request.GetTransactionId()
+	g.mu.Lock()
+	resp, ok := g.committed[txID]
+	g.mu.Unlock()
+	if !ok {
+		return nil, status.Error(codes.NotFound, "transaction "+txID+" is not submitted")
+	}
+
+	return &gatewaypb.CommitStatusResponse{
+		Result:      peer.TxValidationCode(resp.TxValidationCode),
+		BlockNumber: uint64(resp.BlockNumber),
+	}, nil
+}
+
+func (g *fakeGateway) Evaluate(ctx context.Context, request *gatewaypb.EvaluateRequest) (*gatewaypb.EvaluateResponse, error) {
+	inv, err := parseRequest(request.GetProposedTransaction())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.backend.QueryContext(ctx, inv.Channel, string(inv.Args[0]), stringArgs(inv.Args[1:])...)
+	if err != nil {
+		return nil, chaincodeError(err)
+	}
+
+	return &gatewaypb.EvaluateResponse{Result: &peer.Response{Status: chaincodeStatusOK, Payload: resp.Payload}}, nil
+}
+
+// parseRequest returns invocation of signed proposal of gateway.EndorseRequest or gateway.EvaluateRequest
+func parseRequest(signed *peer.SignedProposal) (invocation, error) {
+	inv, err := parseProposal(signed)
+	if err != nil {
+		return invocation{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return inv, nil
+}
+
+// chaincodeError returns error of backend as gateway returns error of chaincode
+func chaincodeError(err error) error {
+	if err == nil {
+		err = errors.New("empty response")
+	}
+	return status.Error(codes.Aborted, err.Error())
+}
+
+func stringArgs(args [][]byte) []string {
+	s := make([]string, len(args))
+	for i, arg := range args {
+		s[i] = string(arg)
+	}
+	return s
+}
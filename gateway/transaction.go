@@ -0,0 +1,219 @@
+package gateway
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"github.com/hyperledger/fabric-protos-go-apiv2/common"
+	"github.com/hyperledger/fabric-protos-go-apiv2/peer"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const nonceSize = 24
+
+// invocation struct for chaincode invocation of proposal
+type invocation struct {
+	TxID      string
+	Channel   string
+	Chaincode string
+	Args      [][]byte
+}
+
+// newProposal returns id of transaction and serialized peer.Proposal invoking method fcn of chaincode with args on behalf of identity
+func newProposal(id Identity, channel string, chaincode string, fcn string, args []string) (string, []byte, error) {
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+	creator, err := id.Serialize()
+	if err != nil {
+		return "", nil, err
+	}
+	txIDHash := sha256.Sum256(append(append([]byte{}, nonce...), creator...))
+	txID := hex.EncodeToString(txIDHash[:])
+
+	chaincodeID := &peer.ChaincodeID{Name: chaincode}
+	extension, err := proto.Marshal(&peer.ChaincodeHeaderExtension{ChaincodeId: chaincodeID})
+	if err != nil {
+		return "", nil, err
+	}
+	channelHeader, err := proto.Marshal(&common.ChannelHeader{
+		Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+		Timestamp: timestamppb.Now(),
+		ChannelId: channel,
+		TxId:      txID,
+		Extension: extension,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	signatureHeader, err := proto.Marshal(&common.SignatureHeader{Creator: creator, Nonce: nonce})
+	if err != nil {
+		return "", nil, err
+	}
+	header, err := proto.Marshal(&common.Header{ChannelHeader: channelHeader, SignatureHeader: signatureHeader})
+	if err != nil {
+		return "", nil, err
+	}
+
+	input := make([][]byte, 0, len(args)+1)
+	input = append(input, []byte(fcn))
+	for _, arg := range args {
+		input = append(input, []byte(arg))
+	}
+	invocationSpec, err := proto.Marshal(&peer.ChaincodeInvocationSpec{ChaincodeSpec: &peer.ChaincodeSpec{
+		Type:        peer.ChaincodeSpec_GOLANG,
+		ChaincodeId: chaincodeID,
+		Input:       &peer.ChaincodeInput{Args: input},
+	}})
+	if err != nil {
+		return "", nil, err
+	}
+	payload, err := proto.Marshal(&peer.ChaincodeProposalPayload{Input: invocationSpec})
+	if err != nil {
+		return "", nil, err
+	}
+
+	proposal, err := proto.Marshal(&peer.Proposal{Header: header, Payload: payload})
+	if err != nil {
+		return "", nil, err
+	}
+	return txID, proposal, nil
+}
+
+// signedProposal returns peer.SignedProposal of proposal signed by identity
+func signedProposal(id Identity, proposal []byte) (*peer.SignedProposal, error) {
+	signature, err := id.Sign(proposal)
+	if err != nil {
+		return nil, err
+	}
+	return &peer.SignedProposal{ProposalBytes: proposal, Signature: signature}, nil
+}
+
+// parseProposal returns chaincode invocation of peer.SignedProposal
+func parseProposal(signed *peer.SignedProposal) (invocation, error) {
+	proposal := &peer.Proposal{}
+	if err := proto.Unmarshal(signed.GetProposalBytes(), proposal); err != nil {
+		return invocation{}, err
+	}
+	header := &common.Header{}
+	if err := proto.Unmarshal(proposal.GetHeader(), header); err != nil {
+		return invocation{}, err
+	}
+	channelHeader := &common.ChannelHeader{}
+	if err := proto.Unmarshal(header.GetChannelHeader(), channelHeader); err != nil {
+		return invocation{}, err
+	}
+	payload := &peer.ChaincodeProposalPayload{}
+	if err := proto.Unmarshal(proposal.GetPayload(), payload); err != nil {
+		return invocation{}, err
+	}
+	invocationSpec := &peer.ChaincodeInvocationSpec{}
+	if err := proto.Unmarshal(payload.GetInput(), invocationSpec); err != nil {
+		return invocation{}, err
+	}
+
+	spec := invocationSpec.GetChaincodeSpec()
+	args := spec.GetInput().GetArgs()
+	if len(args) == 0 {
+		return invocation{}, errors.New("proposal has no chaincode method")
+	}
+
+	return invocation{
+		TxID:      channelHeader.GetTxId(),
+		Channel:   channelHeader.GetChannelId(),
+		Chaincode: spec.GetChaincodeId().GetName(),
+		Args:      args,
+	}, nil
+}
+
+// preparedTransaction returns common.Envelope of transaction with response of chaincode to proposal,
+// envelope is not signed, client signs it before submit
+func preparedTransaction(signed *peer.SignedProposal, response *peer.Response) (*common.Envelope, error) {
+	proposal := &peer.Proposal{}
+	if err := proto.Unmarshal(signed.GetProposalBytes(), proposal); err != nil {
+		return nil, err
+	}
+	header := &common.Header{}
+	if err := proto.Unmarshal(proposal.GetHeader(), header); err != nil {
+		return nil, err
+	}
+	channelHeader := &common.ChannelHeader{}
+	if err := proto.Unmarshal(header.GetChannelHeader(), channelHeader); err != nil {
+		return nil, err
+	}
+	extension := &peer.ChaincodeHeaderExtension{}
+	if err := proto.Unmarshal(channelHeader.GetExtension(), extension); err != nil {
+		return nil, err
+	}
+
+	chaincodeAction, err := proto.Marshal(&peer.ChaincodeAction{Response: response, ChaincodeId: extension.GetChaincodeId()})
+	if err != nil {
+		return nil, err
+	}
+	proposalHash := sha256.Sum256(signed.GetProposalBytes())
+	responsePayload, err := proto.Marshal(&peer.ProposalResponsePayload{ProposalHash: proposalHash[:], Extension: chaincodeAction})
+	if err != nil {
+		return nil, err
+	}
+	actionPayload, err := proto.Marshal(&peer.ChaincodeActionPayload{
+		ChaincodeProposalPayload: proposal.GetPayload(),
+		Action:                   &peer.ChaincodeEndorsedAction{ProposalResponsePayload: responsePayload},
+	})
+	if err != nil {
+		return nil, err
+	}
+	transaction, err := proto.Marshal(&peer.Transaction{Actions: []*peer.TransactionAction{{
+		Header:  header.GetSignatureHeader(),
+		Payload: actionPayload,
+	}}})
+	if err != nil {
+		return nil, err
+	}
+	payload, err := proto.Marshal(&common.Payload{Header: header, Data: transaction})
+	if err != nil {
+		return nil, err
+	}
+
+	return &common.Envelope{Payload: payload}, nil
+}
+
+// signEnvelope returns copy of common.Envelope with payload signed by identity
+func signEnvelope(id Identity, envelope *common.Envelope) (*common.Envelope, error) {
+	signature, err := id.Sign(envelope.GetPayload())
+	if err != nil {
+		return nil, err
+	}
+	return &common.Envelope{Payload: envelope.GetPayload(), Signature: signature}, nil
+}
+
+// transactionResponse returns response of chaincode from first action of transaction in common.Envelope
+func transactionResponse(envelope *common.Envelope) (*peer.Response, error) {
+	payload := &common.Payload{}
+	if err := proto.Unmarshal(envelope.GetPayload(), payload); err != nil {
+		return nil, err
+	}
+	transaction := &peer.Transaction{}
+	if err := proto.Unmarshal(payload.GetData(), transaction); err != nil {
+		return nil, err
+	}
+	if len(transaction.GetActions()) == 0 {
+		return nil, errors.New("transaction has no actions")
+	}
+	actionPayload := &peer.ChaincodeActionPayload{}
+	if err := proto.Unmarshal(transaction.GetActions()[0].GetPayload(), actionPayload); err != nil {
+		return nil, err
+	}
+	responsePayload := &peer.ProposalResponsePayload{}
+	if err := proto.Unmarshal(actionPayload.GetAction().GetProposalResponsePayload(), responsePayload); err != nil {
+		return nil, err
+	}
+	chaincodeAction := &peer.ChaincodeAction{}
+	if err := proto.Unmarshal(responsePayload.GetExtension(), chaincodeAction); err != nil {
+		return nil, err
+	}
+	return chaincodeAction.GetResponse(), nil
+}
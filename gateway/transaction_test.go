@@ -0,0 +1,73 @@
+package gateway
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go-apiv2/common"
+	"github.com/hyperledger/fabric-protos-go-apiv2/msp"
+	"github.com/hyperledger/fabric-protos-go-apiv2/peer"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func testIdentity(t *testing.T) Identity {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return Identity{MSPID: "Org1MSP", Certificate: []byte("certificate"), PrivateKey: key}
+}
+
+func TestProposal(t *testing.T) {
+	id := testIdentity(t)
+	txID, proposalBytes, err := newProposal(id, "channel", "chaincode", "fcn", []string{"a", "b"})
+	require.NoError(t, err)
+
+	proposal := &peer.Proposal{}
+	require.NoError(t, proto.Unmarshal(proposalBytes, proposal))
+	header := &common.Header{}
+	require.NoError(t, proto.Unmarshal(proposal.GetHeader(), header))
+	channelHeader := &common.ChannelHeader{}
+	require.NoError(t, proto.Unmarshal(header.GetChannelHeader(), channelHeader))
+	signatureHeader := &common.SignatureHeader{}
+	require.NoError(t, proto.Unmarshal(header.GetSignatureHeader(), signatureHeader))
+	creator := &msp.SerializedIdentity{}
+	require.NoError(t, proto.Unmarshal(signatureHeader.GetCreator(), creator))
+
+	require.Equal(t, int32(common.HeaderType_ENDORSER_TRANSACTION), channelHeader.GetType())
+	require.Equal(t, "channel", channelHeader.GetChannelId())
+	require.Equal(t, txID, channelHeader.GetTxId())
+	require.Equal(t, "Org1MSP", creator.GetMspid())
+	require.Equal(t, id.Certificate, creator.GetIdBytes())
+	txIDHash := sha256.Sum256(append(append([]byte{}, signatureHeader.GetNonce()...), signatureHeader.GetCreator()...))
+	require.Equal(t, hex.EncodeToString(txIDHash[:]), txID, "tx id is hash of nonce and creator as fabric checks it")
+
+	signed, err := signedProposal(id, proposalBytes)
+	require.NoError(t, err)
+	inv, err := parseProposal(signed)
+	require.NoError(t, err)
+	require.Equal(t, invocation{
+		TxID:      txID,
+		Channel:   "channel",
+		Chaincode: "chaincode",
+		Args:      [][]byte{[]byte("fcn"), []byte("a"), []byte("b")},
+	}, inv)
+
+	prepared, err := preparedTransaction(signed, &peer.Response{Status: chaincodeStatusOK, Payload: []byte("payload")})
+	require.NoError(t, err)
+	envelope, err := signEnvelope(id, prepared)
+	require.NoError(t, err)
+	require.NotEmpty(t, envelope.GetSignature())
+
+	payload := &common.Payload{}
+	require.NoError(t, proto.Unmarshal(envelope.GetPayload(), payload))
+	require.True(t, proto.Equal(header, payload.GetHeader()), "transaction has header of proposal")
+	response, err := transactionResponse(envelope)
+	require.NoError(t, err)
+	require.Equal(t, int32(chaincodeStatusOK), response.GetStatus())
+	require.Equal(t, "payload", string(response.GetPayload()))
+}
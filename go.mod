@@ -4,17 +4,25 @@ go 1.18
 
 require (
 	github.com/btcsuite/btcutil v1.0.2
+	github.com/hyperledger/fabric-protos-go-apiv2 v0.3.3
 	github.com/ozontech/allure-go/pkg/allure v0.6.4
 	github.com/ozontech/allure-go/pkg/framework v0.6.18
-	golang.org/x/crypto v0.1.0
+	github.com/stretchr/testify v1.7.1
+	github.com/tyler-smith/go-bip39 v1.1.0
+	golang.org/x/crypto v0.14.0
+	golang.org/x/text v0.13.0
+	google.golang.org/grpc v1.57.0
+	google.golang.org/protobuf v1.31.0
+	gopkg.in/yaml.v3 v3.0.0
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/golang/protobuf v1.5.3 // indirect
 	github.com/google/uuid v1.3.0 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/stretchr/testify v1.7.1 // indirect
-	golang.org/x/sys v0.1.0 // indirect
-	gopkg.in/yaml.v3 v3.0.0 // indirect
+	golang.org/x/net v0.17.0 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20230803162519-f966b187b2e5 // indirect
 )
@@ -0,0 +1,26 @@
+package utils_test
+
+import (
+	"testing"
+
+	utils "github.com/anoideaopen/testnet-util"
+	"github.com/anoideaopen/testnet-util/fake"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeClient returns fake client with acl, fiat and cc chaincodes and issuer with key derived from seed
+func newFakeClient(t *testing.T) (*fake.Client, utils.Issuer) {
+	t.Helper()
+	client := fake.NewClient()
+	fake.NewACL().Register(client, "acl")
+	fake.NewToken().Register(client, "fiat")
+	fake.NewToken().Register(client, "cc")
+
+	privateKey, _, err := utils.DerivePrivateAndPublicKey([]byte("fake"), "issuer")
+	require.NoError(t, err)
+	base58Check, err := utils.ConvertPrivateKeyToBase58Check(privateKey)
+	require.NoError(t, err)
+	issuer, err := utils.NewIssuer(base58Check)
+	require.NoError(t, err)
+	return client, issuer
+}
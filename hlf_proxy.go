@@ -38,10 +38,15 @@ type ResponseErrorPrx struct {
 }
 
 // Invoke ...
+// Returns response together with ErrTxNotValid if transaction was not committed as VALID
 func Invoke(ctx context.Context, url, token, cc, fcn string, endpoints []string, args ...string) (*ResponsePrx, error) {
 	newCtx, cancel := context.WithTimeout(ctx, InvokeTimeout)
 	defer cancel()
-	return doRequest(newCtx, url, token, "invoke", cc, fcn, endpoints, args...)
+	resp, err := doRequest(newCtx, url, token, "invoke", cc, fcn, endpoints, args...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, resp.CheckValid()
 }
 
 // Query ...
@@ -97,7 +102,7 @@ func doRequest(
 	defer func() {
 		clErr := httpResponse.Body.Close()
 		if clErr != nil {
-			fmt.Printf("body close error: %v\n", err)
+			DefaultLogger().Warn("body close error", "error", clErr)
 		}
 	}()
 	body, err := io.ReadAll(httpResponse.Body)
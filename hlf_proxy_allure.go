@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+// callRequest struct for request attached to allure step, args are decoded and signatures shortened
+type callRequest struct {
+	RequestType string   `json:"requestType"`
+	ChaincodeID string   `json:"chaincodeId"`
+	Fcn         string   `json:"fcn"`
+	Args        []string `json:"args"`
+	Endpoints   []string `json:"targetEndpoints,omitempty"`
+}
+
+// callResponse struct for response attached to allure step, payload is decoded if it is json or text
+type callResponse struct {
+	TransactionID    string          `json:"transactionId,omitempty"`
+	BlockNumber      int64           `json:"blockNumber,omitempty"`
+	TxValidationCode string          `json:"txValidationCode"`
+	ChaincodeStatus  int64           `json:"chaincodeStatus,omitempty"`
+	Payload          json.RawMessage `json:"payload,omitempty"`
+	Error            string          `json:"error,omitempty"`
+}
+
+// WithStep - attach request, response, duration and tx id of every call to allure step
+func WithStep(sCtx provider.StepCtx) HlfProxyOption {
+	return func(p *HlfProxyService) {
+		p.sCtx = sCtx
+	}
+}
+
+// WithObserverTxURL - attach link to observer web page of transaction, tx id is appended to url.
+// By default url is taken from ObserverTxURL env
+func WithObserverTxURL(url string) HlfProxyOption {
+	return func(p *HlfProxyService) {
+		p.observerTxURL = url
+	}
+}
+
+// InStep - create copy of HlfProxyService attaching calls to allure step
+//
+//	t.WithNewStep("Transfer", func(sCtx provider.StepCtx) {
+//		_, err := hlfProxy.InStep(sCtx).Invoke(channel, "transfer", args...)
+//		sCtx.Require().NoError(err)
+//	})
+func (p *HlfProxyService) InStep(sCtx provider.StepCtx) *HlfProxyService {
+	return p.WithOptions(WithStep(sCtx))
+}
+
+// ClientInStep returns client attaching calls to allure step if client is HlfProxyService, other clients are returned as is.
+// Helpers call it inside their steps so proxy calls are attached to the active step without InStep or WithStep
+func ClientInStep(client ChaincodeClient, sCtx provider.StepCtx) ChaincodeClient {
+	if p, ok := client.(*HlfProxyService); ok {
+		return p.InStep(sCtx)
+	}
+	return client
+}
+
+func (p *HlfProxyService) attachCall(
+	requestType string,
+	chaincodeID string,
+	fcn string,
+	args []string,
+	response *Response,
+	err error,
+	duration time.Duration,
+) {
+	if p.sCtx == nil {
+		return
+	}
+
+	name := requestType + " " + chaincodeID + " " + fcn
+	if data, mErr := json.MarshalIndent(callRequest{
+		RequestType: requestType,
+		ChaincodeID: chaincodeID,
+		Fcn:         fcn,
+		Args:        RedactArgs(args),
+		Endpoints:   p.targetEndpoints,
+	}, "", "  "); mErr == nil {
+		p.sCtx.WithNewAttachment(name+" request", allure.JSON, data)
+	}
+
+	res := callResponse{}
+	if response != nil {
+		res.TransactionID = response.TransactionID
+		res.BlockNumber = response.BlockNumber
+		res.TxValidationCode = response.ValidationCode().String()
+		res.ChaincodeStatus = response.ChaincodeStatus
+		res.Payload = payloadJSON(response.Payload)
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	if data, mErr := json.MarshalIndent(res, "", "  "); mErr == nil {
+		p.sCtx.WithNewAttachment(name+" response", allure.JSON, data)
+	}
+
+	p.sCtx.WithNewParameters(name+" duration", duration.String())
+	if response == nil || response.TransactionID == "" {
+		return
+	}
+	p.sCtx.WithNewParameters(name+" tx id", response.TransactionID)
+	if p.observerTxURL != "" {
+		p.sCtx.WithNewAttachment(name+" observer", allure.URIList,
+			[]byte(strings.TrimSuffix(p.observerTxURL, "/")+"/"+response.TransactionID))
+	}
+}
+
+// payloadJSON returns payload as is if it is json, as json string if it is text and as base64 string otherwise
+func payloadJSON(payload []byte) json.RawMessage {
+	if len(payload) == 0 {
+		return nil
+	}
+	if json.Valid(payload) {
+		return payload
+	}
+	var v any = payload
+	if utf8.Valid(payload) {
+		v = string(payload)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return data
+}
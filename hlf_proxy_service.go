@@ -8,6 +8,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
 )
 
 // HlfProxyService struct
@@ -18,38 +21,124 @@ type HlfProxyService struct {
 	url string
 	// authToken - support Basic Auth with auth token
 	authToken string
+	// httpClient - client requests are sent with, http.DefaultClient by default
+	httpClient *http.Client
+	// logger - logger of requests and responses, DefaultLogger if nil
+	logger Logger
+	// sCtx - allure step requests and responses are attached to, nothing is attached if nil
+	sCtx provider.StepCtx
+	// targetEndpoints - names of peers requests are sent to, peers are chosen by hlf proxy if empty
+	targetEndpoints []string
+	// observerTxURL - observer url of transaction page, tx id is appended to it, link is not attached if empty
+	observerTxURL string
+}
+
+// HlfProxyOption - option of HlfProxyService
+type HlfProxyOption func(p *HlfProxyService)
+
+// WithTransport - send requests through transport, example recording or replaying one
+func WithTransport(transport http.RoundTripper) HlfProxyOption {
+	return func(p *HlfProxyService) {
+		p.httpClient = &http.Client{Transport: transport}
+	}
+}
+
+// WithLogger - log requests and responses with logger instead of DefaultLogger,
+// example NewAllureLogger to attach them to current allure step
+func WithLogger(logger Logger) HlfProxyOption {
+	return func(p *HlfProxyService) {
+		p.logger = logger
+	}
+}
+
+// WithTargetEndpoints - send every request to peers with names endpoints instead of peers chosen by hlf proxy
+func WithTargetEndpoints(endpoints ...string) HlfProxyOption {
+	return func(p *HlfProxyService) {
+		p.targetEndpoints = endpoints
+	}
 }
 
 // NewHlfProxyService - create new instance of HlfProxyService
-func NewHlfProxyService(url string, authToken string) *HlfProxyService {
-	return &HlfProxyService{
-		url:       url,
-		authToken: authToken,
+func NewHlfProxyService(url string, authToken string, opts ...HlfProxyOption) *HlfProxyService {
+	p := &HlfProxyService{
+		url:           url,
+		authToken:     authToken,
+		httpClient:    http.DefaultClient,
+		observerTxURL: GetEnv(ObserverTxURL, ""),
+	}
+	for _, opt := range opts {
+		opt(p)
 	}
+	return p
 }
 
-// Invoke - send invoke request to hlf through hlf proxy service
+// WithOptions - create copy of HlfProxyService with options applied, example WithLogger for one step
+func (p *HlfProxyService) WithOptions(opts ...HlfProxyOption) *HlfProxyService {
+	c := *p
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return &c
+}
+
+// OnEndpoints - create copy of HlfProxyService sending requests to peers with names endpoints,
+// example hlfProxy.OnEndpoints(stand.CorrectNodeName).Query(channel, "balanceOf", address)
+func (p *HlfProxyService) OnEndpoints(endpoints ...string) *HlfProxyService {
+	return p.WithOptions(WithTargetEndpoints(endpoints...))
+}
+
+// ClientOnEndpoints - OnEndpoints as ChaincodeClient, implements EndpointsClient
+func (p *HlfProxyService) ClientOnEndpoints(endpoints ...string) ChaincodeClient {
+	return p.OnEndpoints(endpoints...)
+}
+
+// TargetEndpoints returns names of peers requests are sent to, empty if peers are chosen by hlf proxy
+func (p *HlfProxyService) TargetEndpoints() []string {
+	return append([]string{}, p.targetEndpoints...)
+}
+
+// Invoke - send invoke request to hlf through hlf proxy service.
+// Returns response together with ErrTxNotValid if transaction was not committed as VALID
 func (p *HlfProxyService) Invoke(chaincodeID string, fcn string, args ...string) (*Response, error) {
-	return p.sendRequest("invoke", chaincodeID, fcn, args...)
+	return p.InvokeContext(context.Background(), chaincodeID, fcn, args...)
 }
 
 // Query - send query request to hlf through hlf proxy service
 func (p *HlfProxyService) Query(chaincodeID string, fcn string, args ...string) (*Response, error) {
-	return p.sendRequest("query", chaincodeID, fcn, args...)
+	return p.QueryContext(context.Background(), chaincodeID, fcn, args...)
+}
+
+// InvokeContext - send invoke request to hlf through hlf proxy service with context, see Invoke
+func (p *HlfProxyService) InvokeContext(ctx context.Context, chaincodeID string, fcn string, args ...string) (*Response, error) {
+	start := time.Now()
+	response, err := p.doRequest(ctx, "invoke", chaincodeID, fcn, args...)
+	if err == nil {
+		err = response.CheckValid()
+	}
+	// call is attached after validation so INVALID transaction is attached with ErrTxNotValid
+	p.attachCall("invoke", chaincodeID, fcn, args, response, err, time.Since(start))
+	return response, err
+}
+
+// QueryContext - send query request to hlf through hlf proxy service with context, see Query
+func (p *HlfProxyService) QueryContext(ctx context.Context, chaincodeID string, fcn string, args ...string) (*Response, error) {
+	start := time.Now()
+	response, err := p.doRequest(ctx, "query", chaincodeID, fcn, args...)
+	p.attachCall("query", chaincodeID, fcn, args, response, err, time.Since(start))
+	return response, err
 }
 
 //nolint:funlen
-func (p *HlfProxyService) sendRequest(requestType string, chaincodeID string, fcn string, args ...string) (*Response, error) {
-	fmt.Printf("requestType: %s\n", requestType)
-	fmt.Printf("chaincodeID: %s\n", chaincodeID)
-	fmt.Printf("fcn: %s\n", fcn)
-	fmt.Printf("args: %s\n", args)
+func (p *HlfProxyService) doRequest(ctx context.Context, requestType string, chaincodeID string, fcn string, args ...string) (*Response, error) {
+	p.log().Debug("hlf proxy request",
+		"requestType", requestType,
+		"chaincodeID", chaincodeID,
+		"fcn", fcn,
+		"args", RedactArgs(args),
+		"targetEndpoints", p.targetEndpoints,
+	)
 
-	requestData := Request{
-		Args:        AsBytes(args...),
-		ChaincodeID: chaincodeID,
-		Fcn:         fcn,
-	}
+	requestData := p.request(chaincodeID, fcn, args...)
 
 	requestPayload, err := json.Marshal(requestData)
 	if err != nil {
@@ -57,7 +146,7 @@ func (p *HlfProxyService) sendRequest(requestType string, chaincodeID string, fc
 	}
 
 	httpRequest, err := http.NewRequestWithContext(
-		context.Background(),
+		ctx,
 		http.MethodPost,
 		fmt.Sprintf("%s/%s", p.url, requestType),
 		bytes.NewReader(requestPayload),
@@ -69,7 +158,7 @@ func (p *HlfProxyService) sendRequest(requestType string, chaincodeID string, fc
 	httpRequest.Header.Add("authorization", fmt.Sprintf("Basic %s", p.authToken))
 	httpRequest.Header.Add("content-type", "application/json")
 
-	httpResponse, err := http.DefaultClient.Do(httpRequest)
+	httpResponse, err := p.httpClient.Do(httpRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +168,7 @@ func (p *HlfProxyService) sendRequest(requestType string, chaincodeID string, fc
 	defer func() {
 		err = httpResponse.Body.Close()
 		if err != nil {
-			fmt.Println(fmt.Errorf("error close body http response - error: %w", err))
+			p.log().Warn("error close body http response", "error", err)
 		}
 	}()
 	body, err := io.ReadAll(httpResponse.Body)
@@ -94,10 +183,17 @@ func (p *HlfProxyService) sendRequest(requestType string, chaincodeID string, fc
 			return nil, err
 		}
 
+		p.log().Info("hlf proxy error response",
+			"requestType", requestType,
+			"chaincodeID", chaincodeID,
+			"fcn", fcn,
+			"code", responseError.Code,
+			"message", responseError.Message,
+		)
 		return nil, errors.New(responseError.Message)
 	}
 
-	fmt.Println(string(body))
+	p.log().Debug("hlf proxy response", "requestType", requestType, "fcn", fcn, "body", body)
 	response := &Response{}
 	err = json.Unmarshal(body, response)
 	if err != nil {
@@ -118,16 +214,15 @@ func (p *HlfProxyService) GetPayloadQuery(chaincodeID string, fcn string, args .
 }
 
 func (p *HlfProxyService) preparePayload(requestType string, chaincodeID string, fcn string, args ...string) ([]byte, error) {
-	fmt.Printf("requestType: %s\n", requestType)
-	fmt.Printf("chaincodeID: %s\n", chaincodeID)
-	fmt.Printf("fcn: %s\n", fcn)
-	fmt.Printf("args: %s\n", args)
+	p.log().Debug("hlf proxy payload",
+		"requestType", requestType,
+		"chaincodeID", chaincodeID,
+		"fcn", fcn,
+		"args", RedactArgs(args),
+		"targetEndpoints", p.targetEndpoints,
+	)
 
-	requestData := Request{
-		Args:        AsBytes(args...),
-		ChaincodeID: chaincodeID,
-		Fcn:         fcn,
-	}
+	requestData := p.request(chaincodeID, fcn, args...)
 
 	requestPayload, err := json.Marshal(requestData)
 	if err != nil {
@@ -136,3 +231,22 @@ func (p *HlfProxyService) preparePayload(requestType string, chaincodeID string,
 
 	return requestPayload, nil
 }
+
+func (p *HlfProxyService) log() Logger {
+	if p.logger != nil {
+		return p.logger
+	}
+	return DefaultLogger()
+}
+
+func (p *HlfProxyService) request(chaincodeID string, fcn string, args ...string) Request {
+	requestData := Request{
+		Args:        AsBytes(args...),
+		ChaincodeID: chaincodeID,
+		Fcn:         fcn,
+	}
+	if len(p.targetEndpoints) != 0 {
+		requestData.Opts = &Options{TargetEndpoints: p.targetEndpoints}
+	}
+	return requestData
+}
@@ -19,8 +19,10 @@ import (
 // url - domain and port for observer service,
 // example http://localhost:3335/api without '/' on the end the string.
 // HTTPClient is used to send requests to observer service.
+// transport - transport requests are sent through, http.DefaultTransport if nil
 type HTTPClient struct {
-	url string
+	url       string
+	transport http.RoundTripper
 }
 
 // NewHTTPClient - create new instance of HTTPClient
@@ -35,6 +37,12 @@ func NewHTTPClient(url ...string) *HTTPClient {
 	}
 }
 
+// SetTransport - send requests through transport, example recording or replaying one
+func (o *HTTPClient) SetTransport(transport http.RoundTripper) *HTTPClient {
+	o.transport = transport
+	return o
+}
+
 // Post - send POST request to observer service
 func (o *HTTPClient) Post(t provider.T, apiPath string, v any) ([]byte, int) {
 	var (
@@ -54,7 +62,8 @@ func (o *HTTPClient) Post(t provider.T, apiPath string, v any) ([]byte, int) {
 		requestTimeout, err := strconv.Atoi(GetEnv("REQUEST_TIMEOUT", "1"))
 		t.Assert().NoError(err)
 		client = http.Client{
-			Timeout: time.Duration(requestTimeout) * time.Second,
+			Timeout:   time.Duration(requestTimeout) * time.Second,
+			Transport: o.transport,
 		}
 		u = o.PrepareURL(t, apiPath)
 	})
@@ -92,7 +101,8 @@ func (o *HTTPClient) Get(t provider.T, apiPath string) ([]byte, int) {
 		requestTimeout, err := strconv.Atoi(GetEnv("REQUEST_TIMEOUT", "1"))
 		sCtx.Assert().NoError(err)
 		client = http.Client{
-			Timeout: time.Duration(requestTimeout) * time.Second,
+			Timeout:   time.Duration(requestTimeout) * time.Second,
+			Transport: o.transport,
 		}
 		u = o.PrepareURL(t, apiPath)
 	})
@@ -0,0 +1,210 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+// IndustrialBalance - balance of industrial token by groups, group name to amount
+type IndustrialBalance map[string]string
+
+// InitializeIndustrial signs and invokes initialization of industrial token groups from chaincode config by issuer
+func InitializeIndustrial(hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string) (*Response, error) {
+	signedArgs, err := Sign(issuer.IssuerEd25519PrivateKey, issuer.IssuerEd25519PublicKey, channel, chaincode, "initialize", []string{})
+	if err != nil {
+		return nil, err
+	}
+	return hlfProxy.InvokeContext(context.Background(), channel, "initialize", signedArgs...)
+}
+
+// InitializeIndustrialAndCheck initializes industrial token groups and waits for batch execution
+func InitializeIndustrialAndCheck(t provider.T, hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string) {
+	t.WithNewStep("Initialize industrial token in channel "+channel, func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		_, err := InitializeIndustrial(hlfProxy, issuer, channel, chaincode)
+		sCtx.Require().NoError(err)
+		time.Sleep(BatchTransactionTimeout)
+	})
+}
+
+// EmitIndustrial signs and invokes emission of amount of tokens of group to userAddressBase58Check by issuer without waiting for batch execution
+func EmitIndustrial(
+	hlfProxy ChaincodeClient,
+	userAddressBase58Check string,
+	issuer Issuer,
+	channel string,
+	chaincode string,
+	group string,
+	amount string,
+) (*Response, error) {
+	emitArgs := []string{userAddressBase58Check, amount, group}
+	signedEmitArgs, err := Sign(issuer.IssuerEd25519PrivateKey, issuer.IssuerEd25519PublicKey, channel, chaincode, "emitIndustrial", emitArgs)
+	if err != nil {
+		return nil, err
+	}
+	return hlfProxy.InvokeContext(context.Background(), channel, "emitIndustrial", signedEmitArgs...)
+}
+
+// EmitIndustrialGetTxIDAndCheckBalance emits amount of tokens of group to userAddressBase58Check
+// and checks that balance of group is equal to amount
+func EmitIndustrialGetTxIDAndCheckBalance(
+	t provider.T,
+	hlfProxy ChaincodeClient,
+	userAddressBase58Check string,
+	issuer Issuer,
+	channel string,
+	chaincode string,
+	group string,
+	amount string,
+) string {
+	var txID string
+	t.WithNewStep("Emit "+amount+" token of group "+group+" to user "+userAddressBase58Check+" and get txId", func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		res, err := EmitIndustrial(hlfProxy, userAddressBase58Check, issuer, channel, chaincode, group, amount)
+		sCtx.Require().NoError(err)
+		time.Sleep(BatchTransactionTimeout)
+		txID = res.TransactionID
+	})
+
+	CheckIndustrialBalanceEqual(t, hlfProxy, userAddressBase58Check, channel, group, amount)
+	return txID
+}
+
+// EmitIndustrialGroupsAndCheckBalance emits amounts of tokens of every group to userAddressBase58Check
+// and checks that industrial balance is equal to amounts
+func EmitIndustrialGroupsAndCheckBalance(
+	t provider.T,
+	hlfProxy ChaincodeClient,
+	userAddressBase58Check string,
+	issuer Issuer,
+	channel string,
+	chaincode string,
+	amounts IndustrialBalance,
+) {
+	t.WithNewStep("Emit tokens of "+amounts.String()+" to user "+userAddressBase58Check, func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		for _, group := range amounts.Groups() {
+			_, err := EmitIndustrial(hlfProxy, userAddressBase58Check, issuer, channel, chaincode, group, amounts[group])
+			sCtx.Require().NoError(err)
+		}
+		time.Sleep(BatchTransactionTimeout)
+	})
+
+	CheckIndustrialBalancesEqual(t, hlfProxy, userAddressBase58Check, channel, amounts)
+}
+
+// TransferIndustrial signs by userFrom and invokes transfer of amount of tokens of group to userToAddress without waiting for batch execution
+func TransferIndustrial(
+	hlfProxy ChaincodeClient,
+	userFrom User,
+	userToAddress string,
+	channel string,
+	chaincode string,
+	group string,
+	amount string,
+	ref string,
+) (*Response, error) {
+	transferArgs := []string{userToAddress, amount, group, ref}
+	signedTransferArgs, err := Sign(userFrom.UserEd25519PrivateKey, userFrom.UserEd25519PublicKey, channel, chaincode, "transferIndustrial", transferArgs)
+	if err != nil {
+		return nil, err
+	}
+	return hlfProxy.InvokeContext(context.Background(), channel, "transferIndustrial", signedTransferArgs...)
+}
+
+// TransferIndustrialCheckBalanceAndGetResponse transfers amount of tokens of group from userFrom to userToAddress
+// and checks that balance of group of userToAddress is equal to amount
+func TransferIndustrialCheckBalanceAndGetResponse(
+	t provider.T,
+	hlfProxy ChaincodeClient,
+	userFrom User,
+	userToAddress string,
+	channel string,
+	chaincode string,
+	group string,
+	amount string,
+) *Response {
+	var res *Response
+	t.WithNewStep("Transfer "+amount+" token of group "+group+" to user "+userToAddress, func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		var err error
+		res, err = TransferIndustrial(hlfProxy, userFrom, userToAddress, channel, chaincode, group, amount, "ref transfer")
+		sCtx.Require().NoError(err)
+		time.Sleep(BatchTransactionTimeout)
+	})
+
+	CheckIndustrialBalanceEqual(t, hlfProxy, userToAddress, channel, group, amount)
+	return res
+}
+
+// GetIndustrialBalance returns industrial balance of userAddressBase58Check by groups
+func GetIndustrialBalance(hlfProxy ChaincodeClient, userAddressBase58Check string, channel string) (IndustrialBalance, error) {
+	resp, err := hlfProxy.QueryContext(context.Background(), channel, "industrialBalanceOf", userAddressBase58Check)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := IndustrialBalance{}
+	if err = json.Unmarshal(resp.Payload, &balance); err != nil {
+		return nil, fmt.Errorf("json unmarshal industrial balance: %w", err)
+	}
+	return balance, nil
+}
+
+// CheckIndustrialBalanceEqual checks that balance of group of userAddressBase58Check is equal to amount
+func CheckIndustrialBalanceEqual(t provider.T, hlfProxy ChaincodeClient, userAddressBase58Check string, channel string, group string, amount string) {
+	t.WithNewStep("Checking that balance of group "+group+" equal "+amount, func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		balance, err := GetIndustrialBalance(hlfProxy, userAddressBase58Check, channel)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal(amount, balance.Amount(group))
+	})
+}
+
+// CheckIndustrialBalancesEqual checks that balance of every group of expected is equal to its amount,
+// balances of other groups are not checked
+func CheckIndustrialBalancesEqual(t provider.T, hlfProxy ChaincodeClient, userAddressBase58Check string, channel string, expected IndustrialBalance) {
+	t.WithNewStep("Checking that industrial balance equal "+expected.String(), func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		balance, err := GetIndustrialBalance(hlfProxy, userAddressBase58Check, channel)
+		sCtx.Require().NoError(err)
+		for _, group := range expected.Groups() {
+			sCtx.Require().Equal(expected[group], balance.Amount(group), "balance of group %s", group)
+		}
+	})
+}
+
+// Amount returns amount of group, "0" if there is no group in balance
+func (b IndustrialBalance) Amount(group string) string {
+	if amount, ok := b[group]; ok {
+		return amount
+	}
+	return "0"
+}
+
+// Groups returns sorted names of groups
+func (b IndustrialBalance) Groups() []string {
+	groups := make([]string, 0, len(b))
+	for group := range b {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// String returns groups with amounts in order of names, example 202009:100, 202010:50
+func (b IndustrialBalance) String() string {
+	s := ""
+	for i, group := range b.Groups() {
+		if i > 0 {
+			s += ", "
+		}
+		s += group + ":" + b[group]
+	}
+	return s
+}
@@ -0,0 +1,146 @@
+package utils_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	utils "github.com/anoideaopen/testnet-util"
+	"github.com/anoideaopen/testnet-util/fake"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndustrialBalance(t *testing.T) {
+	tests := []struct {
+		name    string
+		balance utils.IndustrialBalance
+		groups  []string
+		str     string
+	}{
+		{name: "empty", groups: []string{}},
+		{
+			name:    "groups in order of names",
+			balance: utils.IndustrialBalance{"202010": "50", "202009": "100"},
+			groups:  []string{"202009", "202010"},
+			str:     "202009:100, 202010:50",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.groups, tt.balance.Groups())
+			require.Equal(t, tt.str, tt.balance.String())
+			for _, group := range tt.groups {
+				require.Equal(t, tt.balance[group], tt.balance.Amount(group))
+			}
+			require.Equal(t, "0", tt.balance.Amount("unknown"), "amount of missing group")
+		})
+	}
+}
+
+// industrialToken - fake of industrial token chaincode, balances are kept by address and group
+type industrialToken struct {
+	mu       sync.Mutex
+	balances map[string]map[string]*big.Int
+}
+
+func (it *industrialToken) register(client *fake.Client, chaincodeID string) {
+	it.balances = make(map[string]map[string]*big.Int)
+	client.
+		Handle(chaincodeID, "emitIndustrial", func(_ context.Context, args []string) ([]byte, error) {
+			// signed args: address, amount, group
+			it.add(args[3], args[5], args[4], 1)
+			return nil, nil
+		}).
+		Handle(chaincodeID, "transferIndustrial", func(_ context.Context, args []string) ([]byte, error) {
+			// signed args: address to, amount, group, ref
+			from, err := utils.GetAddressByPublicKeyBase58(args[len(args)-2])
+			if err != nil {
+				return nil, err
+			}
+			if !it.add(from, args[5], args[4], -1) {
+				return nil, errors.New("insufficient funds")
+			}
+			it.add(args[3], args[5], args[4], 1)
+			return nil, nil
+		}).
+		Handle(chaincodeID, "industrialBalanceOf", func(_ context.Context, args []string) ([]byte, error) {
+			it.mu.Lock()
+			defer it.mu.Unlock()
+			balance := utils.IndustrialBalance{}
+			for group, amount := range it.balances[args[0]] {
+				balance[group] = amount.String()
+			}
+			return json.Marshal(balance)
+		})
+}
+
+// add adds amount multiplied by sign to balance of group, false if balance becomes negative
+func (it *industrialToken) add(address string, group string, amount string, sign int64) bool {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	if it.balances[address] == nil {
+		it.balances[address] = make(map[string]*big.Int)
+	}
+	value, _ := new(big.Int).SetString(amount, 10)
+	balance := new(big.Int).Mul(value, big.NewInt(sign))
+	if current, ok := it.balances[address][group]; ok {
+		balance.Add(balance, current)
+	}
+	if balance.Sign() < 0 {
+		return false
+	}
+	it.balances[address][group] = balance
+	return true
+}
+
+func TestIndustrialEmitTransfer(t *testing.T) {
+	client, issuer := newFakeClient(t)
+	(&industrialToken{}).register(client, "indust")
+	users := make([]utils.User, 2)
+	for i, name := range []string{"alice", "bob"} {
+		privateKey, _, err := utils.DerivePrivateAndPublicKey([]byte("industrial"), name)
+		require.NoError(t, err)
+		users[i], err = utils.NewUser(privateKey)
+		require.NoError(t, err)
+	}
+	alice, bob := users[0].UserAddressBase58Check, users[1].UserAddressBase58Check
+
+	emitted := utils.IndustrialBalance{"202009": "100", "202010": "50"}
+	for group, amount := range emitted {
+		_, err := utils.EmitIndustrial(client, alice, issuer, "indust", "indust", group, amount)
+		require.NoError(t, err)
+	}
+	_, err := utils.TransferIndustrial(client, users[0], bob, "indust", "indust", "202009", "30", "ref transfer")
+	require.NoError(t, err)
+	_, err = utils.TransferIndustrial(client, users[0], bob, "indust", "indust", "202010", "60", "ref transfer")
+	require.Error(t, err, "transfer more than balance of group")
+
+	tests := []struct {
+		name    string
+		address string
+		want    utils.IndustrialBalance
+	}{
+		{name: "sender", address: alice, want: utils.IndustrialBalance{"202009": "70", "202010": "50"}},
+		{name: "receiver", address: bob, want: utils.IndustrialBalance{"202009": "30"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balance, err := utils.GetIndustrialBalance(client, tt.address, "indust")
+			require.NoError(t, err)
+			require.Equal(t, tt.want, balance)
+		})
+	}
+}
+
+func TestGetIndustrialBalanceInvalidPayload(t *testing.T) {
+	client := fake.NewClient().
+		Handle("indust", "industrialBalanceOf", func(context.Context, []string) ([]byte, error) { return []byte(`"100"`), nil })
+
+	_, err := utils.GetIndustrialBalance(client, "address", "indust")
+	var jsonErr *json.UnmarshalTypeError
+	require.ErrorAs(t, err, &jsonErr)
+	require.ErrorContains(t, err, "json unmarshal industrial balance")
+}
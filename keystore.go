@@ -0,0 +1,252 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+const (
+	// KeystorePath - path to json file with test identities
+	KeystorePath = "KEYSTORE_PATH"
+	// DefaultKeystorePath - path to keystore used when KeystorePath is not set
+	DefaultKeystorePath = "keystore.json"
+	// RoleUser - role of identity created by AddUser
+	RoleUser = "user"
+	// RoleIssuer - role of token issuer identity
+	RoleIssuer = "issuer"
+)
+
+// KeystoreEntry struct for identity saved in keystore
+type KeystoreEntry struct {
+	// Alias - name of identity unique in keystore
+	Alias string `json:"alias"`
+	// PrivateKey - private key ed25519 in base58 check
+	PrivateKey string `json:"privateKey"`
+	// PublicKey - public key ed25519 in base58
+	PublicKey string `json:"publicKey"`
+	// Address - address in base58 check
+	Address string   `json:"address"`
+	Role    string   `json:"role"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// Keystore struct
+// path - json file keystore is loaded from and saved to
+// entries - identities in order they were added
+type Keystore struct {
+	path    string
+	mu      sync.Mutex
+	entries []KeystoreEntry
+}
+
+// LoadKeystore - load keystore from json file, keystore is empty if file does not exist
+func LoadKeystore(path string) (*Keystore, error) {
+	ks := &Keystore{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ks, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read keystore: %w", err)
+	}
+
+	if err = json.Unmarshal(data, &ks.entries); err != nil {
+		return nil, fmt.Errorf("json unmarshal keystore: %w", err)
+	}
+
+	return ks, nil
+}
+
+// LoadKeystoreFromEnv - load keystore from file in KeystorePath env
+func LoadKeystoreFromEnv() (*Keystore, error) {
+	return LoadKeystore(GetEnv(KeystorePath, DefaultKeystorePath))
+}
+
+// Save - write keystore to json file it was loaded from,
+// keystore is written to temp file in same directory and renamed over file so it is never left partially written
+func (k *Keystore) Save() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	data, err := json.MarshalIndent(k.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("json marshal keystore: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(k.path), filepath.Base(k.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp keystore: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write keystore: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("write keystore: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), k.path); err != nil {
+		return fmt.Errorf("rename keystore: %w", err)
+	}
+
+	return nil
+}
+
+// Entries returns copy of all identities in keystore
+func (k *Keystore) Entries() []KeystoreEntry {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	entries := make([]KeystoreEntry, len(k.entries))
+	copy(entries, k.entries)
+	return entries
+}
+
+// Put adds entry to keystore, entry with same alias is replaced
+func (k *Keystore) Put(entry KeystoreEntry) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for i, e := range k.entries {
+		if e.Alias == entry.Alias {
+			k.entries[i] = entry
+			return
+		}
+	}
+	k.entries = append(k.entries, entry)
+}
+
+// PutUser adds user to keystore with role RoleUser
+func (k *Keystore) PutUser(alias string, user User, tags ...string) error {
+	privateKey, err := ConvertPrivateKeyToBase58Check(user.UserEd25519PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	k.Put(KeystoreEntry{
+		Alias:      alias,
+		PrivateKey: privateKey,
+		PublicKey:  user.UserPublicKeyBase58,
+		Address:    user.UserAddressBase58Check,
+		Role:       RoleUser,
+		Tags:       tags,
+	})
+	return nil
+}
+
+// PutIssuer adds issuer to keystore with role RoleIssuer
+func (k *Keystore) PutIssuer(alias string, issuer Issuer, tags ...string) error {
+	privateKey, err := ConvertPrivateKeyToBase58Check(issuer.IssuerEd25519PrivateKey)
+	if err != nil {
+		return err
+	}
+	address, err := GetAddressByPublicKey(issuer.IssuerEd25519PublicKey)
+	if err != nil {
+		return err
+	}
+
+	k.Put(KeystoreEntry{
+		Alias:      alias,
+		PrivateKey: privateKey,
+		PublicKey:  issuer.IssuerEd25519PublicKeyBase58,
+		Address:    address,
+		Role:       RoleIssuer,
+		Tags:       tags,
+	})
+	return nil
+}
+
+// ByAlias returns entry by alias
+func (k *Keystore) ByAlias(alias string) (KeystoreEntry, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for _, e := range k.entries {
+		if e.Alias == alias {
+			return e, true
+		}
+	}
+	return KeystoreEntry{}, false
+}
+
+// ByAddress returns entry by address in base58 check
+func (k *Keystore) ByAddress(address string) (KeystoreEntry, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for _, e := range k.entries {
+		if e.Address == address {
+			return e, true
+		}
+	}
+	return KeystoreEntry{}, false
+}
+
+// User restores user from entry
+func (e KeystoreEntry) User() (User, error) {
+	privateKey, _, err := GetPrivateKeyFromBase58Check(e.PrivateKey)
+	if err != nil {
+		return User{}, err
+	}
+	return NewUser(privateKey)
+}
+
+// Issuer restores issuer from entry
+func (e KeystoreEntry) Issuer() (Issuer, error) {
+	return NewIssuer(e.PrivateKey)
+}
+
+// GetOrAddUser returns user saved in keystore by alias if it is still registered in acl channel of stand selected by env,
+// otherwise adds user in acl and saves it to keystore with alias
+func GetOrAddUser(t provider.T, hlfProxy ChaincodeClient, keystore *Keystore, alias string, tags ...string) User {
+	return GetOrAddUserToNetwork(t, hlfProxy, standNetwork(t), keystore, alias, tags...)
+}
+
+// GetOrAddUserToNetwork returns user saved in keystore by alias if it is still registered in acl channel of network,
+// otherwise adds user in acl and saves it to keystore with alias
+func GetOrAddUserToNetwork(t provider.T, hlfProxy ChaincodeClient, network Network, keystore *Keystore, alias string, tags ...string) User {
+	var (
+		user       User
+		registered bool
+	)
+
+	entry, found := keystore.ByAlias(alias)
+	if found {
+		t.WithNewStep("Restore user "+alias+" from keystore and check it is registered in acl", func(sCtx provider.StepCtx) {
+			hlfProxy := ClientInStep(hlfProxy, sCtx)
+			var err error
+			user, err = entry.User()
+			sCtx.Require().NoError(err)
+			_, err = hlfProxy.QueryContext(context.Background(), network.Channel(ChannelACL).Name, "checkKeys", user.UserPublicKeyBase58)
+			registered = err == nil
+		})
+	}
+
+	switch {
+	case registered:
+		return user
+	case found:
+		user = AddUserWithPrivateKeyToNetwork(t, hlfProxy, network, user.UserEd25519PrivateKey)
+	default:
+		user = AddUserToNetwork(t, hlfProxy, network)
+	}
+
+	t.WithNewStep("Save user "+alias+" to keystore", func(sCtx provider.StepCtx) {
+		if found && len(tags) == 0 {
+			tags = entry.Tags
+		}
+		sCtx.Require().NoError(keystore.PutUser(alias, user, tags...))
+		sCtx.Require().NoError(keystore.Save())
+	})
+
+	return user
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeystoreSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	ks, err := LoadKeystore(path)
+	require.NoError(t, err)
+	require.Empty(t, ks.Entries(), "keystore is empty if file does not exist")
+
+	privateKey, _, err := DerivePrivateAndPublicKey([]byte("keystore"), "alice")
+	require.NoError(t, err)
+	user, err := NewUser(privateKey)
+	require.NoError(t, err)
+	require.NoError(t, ks.PutUser("alice", user, "load"))
+
+	issuerKey, _, err := DerivePrivateAndPublicKey([]byte("keystore"), "issuer")
+	require.NoError(t, err)
+	issuerKeyBase58Check, err := ConvertPrivateKeyToBase58Check(issuerKey)
+	require.NoError(t, err)
+	issuer, err := NewIssuer(issuerKeyBase58Check)
+	require.NoError(t, err)
+	require.NoError(t, ks.PutIssuer("issuer", issuer))
+
+	require.NoError(t, ks.Save())
+	files, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Len(t, files, 1, "temp file is renamed over keystore")
+
+	loaded, err := LoadKeystore(path)
+	require.NoError(t, err)
+	require.Equal(t, ks.Entries(), loaded.Entries())
+
+	entry, ok := loaded.ByAlias("alice")
+	require.True(t, ok)
+	require.Equal(t, RoleUser, entry.Role)
+	require.Equal(t, []string{"load"}, entry.Tags)
+	restored, err := entry.User()
+	require.NoError(t, err)
+	require.Equal(t, user.UserAddressBase58Check, restored.UserAddressBase58Check)
+
+	entry, ok = loaded.ByAddress(user.UserAddressBase58Check)
+	require.True(t, ok)
+	require.Equal(t, "alice", entry.Alias)
+
+	entry, ok = loaded.ByAlias("issuer")
+	require.True(t, ok)
+	require.Equal(t, RoleIssuer, entry.Role)
+	restoredIssuer, err := entry.Issuer()
+	require.NoError(t, err)
+	require.Equal(t, issuer.IssuerEd25519PublicKeyBase58, restoredIssuer.IssuerEd25519PublicKeyBase58)
+}
+
+func TestKeystoreLookup(t *testing.T) {
+	ks := &Keystore{}
+	ks.Put(KeystoreEntry{Alias: "alice", Address: "addr1", Role: RoleUser})
+	ks.Put(KeystoreEntry{Alias: "bob", Address: "addr2", Role: RoleUser})
+	ks.Put(KeystoreEntry{Alias: "alice", Address: "addr3", Role: RoleUser})
+
+	tests := []struct {
+		name  string
+		find  func() (KeystoreEntry, bool)
+		alias string
+		found bool
+	}{
+		{name: "by alias", find: func() (KeystoreEntry, bool) { return ks.ByAlias("bob") }, alias: "bob", found: true},
+		{name: "duplicate alias is replaced", find: func() (KeystoreEntry, bool) { return ks.ByAddress("addr3") }, alias: "alice", found: true},
+		{name: "replaced address", find: func() (KeystoreEntry, bool) { return ks.ByAddress("addr1") }},
+		{name: "unknown alias", find: func() (KeystoreEntry, bool) { return ks.ByAlias("carol") }},
+		{name: "unknown address", find: func() (KeystoreEntry, bool) { return ks.ByAddress("addr4") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, ok := tt.find()
+			require.Equal(t, tt.found, ok)
+			require.Equal(t, tt.alias, entry.Alias)
+		})
+	}
+	require.Len(t, ks.Entries(), 2)
+}
+
+func TestLoadKeystoreInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+
+	_, err := LoadKeystore(path)
+	require.ErrorContains(t, err, "json unmarshal keystore")
+}
+
+func TestKeystoreSaveMissingDir(t *testing.T) {
+	ks, err := LoadKeystore(filepath.Join(t.TempDir(), "missing", "keystore.json"))
+	require.NoError(t, err)
+	require.Error(t, ks.Save())
+}
@@ -0,0 +1,149 @@
+package load
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	utils "github.com/anoideaopen/testnet-util"
+)
+
+// MaxRate - maximum rate of operations per second, ticker interval can't be less than nanosecond
+const MaxRate = int(time.Second)
+
+// Operation - single operation of load made by user from, to is other user from pool for operations with receiver.
+// ctx is context passed to Run, operations in flight when Duration expires are finished
+type Operation func(ctx context.Context, from utils.User, to utils.User) error
+
+// NamedOperation struct for operation with name used in report
+type NamedOperation struct {
+	Name string
+	Op   Operation
+}
+
+// Config struct for load configuration
+// Rate - operations per second over all workers, operation is started every 1/Rate second whether previous
+// operations are finished or not; operations go one after another in every worker without pauses if zero
+// Duration - time of load, must be positive
+// Workers - number of goroutines making operations, with Rate it is maximum number of operations in flight,
+// operation is skipped and counted in Report.Missed if all workers are busy at its time
+// Users - pool of users operations are made by, at least two users to make operations with other user as receiver,
+// see utils.UserPool for pre-funded users
+// Sentinels - errors failed operations are grouped by in addition to DefaultSentinels, example fake.ErrUnknownMethod
+type Config struct {
+	Rate      int
+	Duration  time.Duration
+	Workers   int
+	Users     []utils.User
+	Sentinels []error
+}
+
+// Validate returns error if config can't be run
+func (cfg Config) Validate() error {
+	switch {
+	case cfg.Duration <= 0:
+		return fmt.Errorf("duration must be positive, got %s", cfg.Duration)
+	case cfg.Rate < 0 || cfg.Rate > MaxRate:
+		return fmt.Errorf("rate must be from 0 to %d, got %d", MaxRate, cfg.Rate)
+	case cfg.Workers < 0:
+		return fmt.Errorf("workers must not be negative, got %d", cfg.Workers)
+	case len(cfg.Users) < 2: //nolint:gomnd
+		return fmt.Errorf("at least 2 users are required, got %d", len(cfg.Users))
+	}
+	return nil
+}
+
+type job struct {
+	op   NamedOperation
+	from utils.User
+	to   utils.User
+}
+
+// Run makes operations in turn until Duration expires or ctx is done and returns report, see Config
+func Run(ctx context.Context, cfg Config, ops ...NamedOperation) (*Report, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if len(ops) == 0 {
+		return nil, errors.New("no operations")
+	}
+	if cfg.Workers == 0 {
+		cfg.Workers = 1
+	}
+
+	opCtx := ctx
+	ctx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+
+	collector := newCollector(cfg.Sentinels...)
+	started := time.Now()
+	if cfg.Rate > 0 {
+		runOpenLoop(ctx, opCtx, cfg, ops, collector)
+	} else {
+		runClosedLoop(ctx, opCtx, cfg, ops, collector)
+	}
+
+	return collector.report(time.Since(started)), nil
+}
+
+// runClosedLoop makes operations with opCtx from Workers goroutines one after another until ctx is done
+func runClosedLoop(ctx context.Context, opCtx context.Context, cfg Config, ops []NamedOperation, collector *collector) {
+	wg := &sync.WaitGroup{}
+	for w := 0; w < cfg.Workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; ctx.Err() == nil; i += cfg.Workers {
+				collector.run(opCtx, newJob(cfg.Users, ops, i))
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// runOpenLoop starts operation with opCtx every 1/Rate second keeping at most Workers operations in flight until ctx is done
+func runOpenLoop(ctx context.Context, opCtx context.Context, cfg Config, ops []NamedOperation, collector *collector) {
+	ticker := time.NewTicker(time.Second / time.Duration(cfg.Rate))
+	defer ticker.Stop()
+
+	slots := make(chan struct{}, cfg.Workers)
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	for i := 0; ; i++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		j := newJob(cfg.Users, ops, i)
+		select {
+		case slots <- struct{}{}:
+		default:
+			collector.miss(j.op.Name)
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			collector.run(opCtx, j)
+		}()
+	}
+}
+
+// newJob returns i-th operation made by random user to other random user
+func newJob(users []utils.User, ops []NamedOperation, i int) job {
+	from := rand.Intn(len(users))                           //nolint:gosec
+	to := (from + 1 + rand.Intn(len(users)-1)) % len(users) //nolint:gosec
+	return job{
+		op:   ops[i%len(ops)],
+		from: users[from],
+		to:   users[to],
+	}
+}
@@ -0,0 +1,99 @@
+package load
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	utils "github.com/anoideaopen/testnet-util"
+	"github.com/stretchr/testify/require"
+)
+
+func testUsers(t *testing.T, n int) []utils.User {
+	t.Helper()
+	users := make([]utils.User, n)
+	for i := range users {
+		privateKey, _, err := utils.DerivePrivateAndPublicKeyByIndex([]byte("load"), i)
+		require.NoError(t, err)
+		users[i], err = utils.NewUser(privateKey)
+		require.NoError(t, err)
+	}
+	return users
+}
+
+func TestConfigValidate(t *testing.T) {
+	users := testUsers(t, 2)
+
+	tests := []struct {
+		name string
+		cfg  Config
+		err  bool
+	}{
+		{name: "valid", cfg: Config{Rate: 10, Duration: time.Second, Workers: 2, Users: users}},
+		{name: "without rate", cfg: Config{Duration: time.Second, Users: users}},
+		{name: "zero duration", cfg: Config{Rate: 10, Users: users}, err: true},
+		{name: "negative rate", cfg: Config{Rate: -1, Duration: time.Second, Users: users}, err: true},
+		{name: "rate above max", cfg: Config{Rate: MaxRate + 1, Duration: time.Second, Users: users}, err: true},
+		{name: "negative workers", cfg: Config{Duration: time.Second, Workers: -1, Users: users}, err: true},
+		{name: "one user", cfg: Config{Duration: time.Second, Users: users[:1]}, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.err {
+				require.Error(t, err)
+				_, err = Run(context.Background(), tt.cfg, NamedOperation{Name: "noop", Op: func(context.Context, utils.User, utils.User) error { return nil }})
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNewJobExcludesSelfTransfer(t *testing.T) {
+	users := testUsers(t, 2)
+	ops := []NamedOperation{{Name: "a"}, {Name: "b"}}
+	for i := 0; i < 100; i++ {
+		j := newJob(users, ops, i)
+		require.NotEqual(t, j.from.UserAddressBase58Check, j.to.UserAddressBase58Check)
+		require.Equal(t, ops[i%2].Name, j.op.Name)
+	}
+}
+
+func TestRunHoldsRate(t *testing.T) {
+	users := testUsers(t, 3)
+	var mu sync.Mutex
+	started := 0
+	slow := NamedOperation{Name: "slow", Op: func(context.Context, utils.User, utils.User) error {
+		mu.Lock()
+		started++
+		mu.Unlock()
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	}}
+
+	tests := []struct {
+		name    string
+		workers int
+		missed  bool
+	}{
+		{name: "enough workers", workers: 20},
+		{name: "busy workers", workers: 1, missed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started = 0
+			report, err := Run(context.Background(), Config{Rate: 100, Duration: 300 * time.Millisecond, Workers: tt.workers, Users: users}, slow)
+			require.NoError(t, err)
+			require.Equal(t, started, report.Total)
+			if tt.missed {
+				require.Positive(t, report.Missed)
+				require.Less(t, report.Total, 10)
+				return
+			}
+			require.Greater(t, report.Total, 15, "operations are started on schedule while previous ones are in flight")
+		})
+	}
+}
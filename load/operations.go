@@ -0,0 +1,101 @@
+package load
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	utils "github.com/anoideaopen/testnet-util"
+)
+
+const transferIDLen = 16
+
+// signerNonces - last nonce of every signer by its public key, operations of one signer started in the same millisecond
+// get different nonces, chaincode rejects repeated nonce
+var signerNonces sync.Map
+
+// nextNonce returns nonce of signer greater than all previous nonces of signer, first nonce is current time in milliseconds
+func nextNonce(publicKey ed25519.PublicKey) string {
+	last, _ := signerNonces.LoadOrStore(string(publicKey), new(int64))
+	counter := last.(*int64)
+	for {
+		prev := atomic.LoadInt64(counter)
+		next := time.Now().UnixMilli()
+		if next <= prev {
+			next = prev + 1
+		}
+		if atomic.CompareAndSwapInt64(counter, prev, next) {
+			return strconv.FormatInt(next, 10)
+		}
+	}
+}
+
+// Emit returns operation emitting amount of tokens in channel to user from by issuer
+func Emit(hlfProxy utils.ChaincodeClient, channel utils.Channel, issuer utils.Issuer, amount string) NamedOperation {
+	return NamedOperation{
+		Name: "emit",
+		Op: func(ctx context.Context, from utils.User, _ utils.User) error {
+			return invokeSigned(ctx, hlfProxy, issuer.IssuerEd25519PrivateKey, issuer.IssuerEd25519PublicKey, channel,
+				"emit", from.UserAddressBase58Check, amount)
+		},
+	}
+}
+
+// Transfer returns operation transferring amount of tokens in channel from user from to user to
+func Transfer(hlfProxy utils.ChaincodeClient, channel utils.Channel, amount string) NamedOperation {
+	return NamedOperation{
+		Name: "transfer",
+		Op: func(ctx context.Context, from utils.User, to utils.User) error {
+			return invokeSigned(ctx, hlfProxy, from.UserEd25519PrivateKey, from.UserEd25519PublicKey, channel,
+				"transfer", to.UserAddressBase58Check, amount, "ref transfer")
+		},
+	}
+}
+
+// SwapBegin returns operation starting swap of amount of tokens from channel chFrom to channel chTo by user from
+func SwapBegin(hlfProxy utils.ChaincodeClient, chFrom utils.Channel, chTo utils.Channel, amount string) NamedOperation {
+	return NamedOperation{
+		Name: "swapBegin",
+		Op: func(ctx context.Context, from utils.User, _ utils.User) error {
+			return invokeSigned(ctx, hlfProxy, from.UserEd25519PrivateKey, from.UserEd25519PublicKey, chFrom,
+				"swapBegin", chFrom.Ticker, chTo.Ticker, amount, utils.DefaultSwapHash)
+		},
+	}
+}
+
+// ChannelTransfer returns operation transferring amount of tokens of channel chFrom to channel chTo by user from
+func ChannelTransfer(hlfProxy utils.ChaincodeClient, chFrom utils.Channel, chTo utils.Channel, amount string) NamedOperation {
+	return NamedOperation{
+		Name: "channelTransferByCustomer",
+		Op: func(ctx context.Context, from utils.User, _ utils.User) error {
+			id := make([]byte, transferIDLen)
+			if _, err := rand.Read(id); err != nil {
+				return err
+			}
+			return invokeSigned(ctx, hlfProxy, from.UserEd25519PrivateKey, from.UserEd25519PublicKey, chFrom,
+				"channelTransferByCustomer", hex.EncodeToString(id), chTo.Ticker, chFrom.Ticker, amount)
+		},
+	}
+}
+
+func invokeSigned(
+	ctx context.Context,
+	hlfProxy utils.ChaincodeClient,
+	privateKey ed25519.PrivateKey,
+	publicKey ed25519.PublicKey,
+	channel utils.Channel,
+	fcn string,
+	args ...string,
+) error {
+	signed, err := utils.SignWithNonce(privateKey, publicKey, channel.Name, channel.Chaincode, fcn, args, nextNonce(publicKey))
+	if err != nil {
+		return err
+	}
+	_, err = hlfProxy.InvokeContext(ctx, channel.Name, fcn, signed...)
+	return err
+}
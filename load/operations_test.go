@@ -0,0 +1,66 @@
+package load
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	utils "github.com/anoideaopen/testnet-util"
+	"github.com/anoideaopen/testnet-util/fake"
+	"github.com/stretchr/testify/require"
+)
+
+// nonceArgFromEnd - position of nonce from the end of signed args: nonce, public key, signature
+const nonceArgFromEnd = 3
+
+func TestOperationsNonceUniquePerSigner(t *testing.T) {
+	const ops = 200
+	users := testUsers(t, 2)
+	issuer := utils.Issuer{
+		IssuerEd25519PrivateKey: users[1].UserEd25519PrivateKey,
+		IssuerEd25519PublicKey:  users[1].UserEd25519PublicKey,
+	}
+	channel := utils.Channel{Name: "fiat", Chaincode: "fiat", Ticker: "FIAT"}
+
+	var mu sync.Mutex
+	nonces := make(map[string][]string)
+	record := func(_ context.Context, args []string) ([]byte, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		signer := args[len(args)-2]
+		nonces[signer] = append(nonces[signer], args[len(args)-nonceArgFromEnd])
+		return nil, nil
+	}
+	client := fake.NewClient().Handle("fiat", "emit", record).Handle("fiat", "transfer", record)
+
+	tests := []struct {
+		name string
+		op   NamedOperation
+	}{
+		{name: "emit signed by issuer", op: Emit(client, channel, issuer, "1")},
+		{name: "transfer signed by user", op: Transfer(client, channel, "1")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nonces = make(map[string][]string)
+			var wg sync.WaitGroup
+			for i := 0; i < ops; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					require.NoError(t, tt.op.Op(context.Background(), users[0], users[1]))
+				}()
+			}
+			wg.Wait()
+
+			require.Len(t, nonces, 1, "operations are signed by one signer")
+			for _, signed := range nonces {
+				unique := make(map[string]struct{}, len(signed))
+				for _, nonce := range signed {
+					unique[nonce] = struct{}{}
+				}
+				require.Len(t, unique, ops)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package load
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	utils "github.com/anoideaopen/testnet-util"
+	"github.com/ozontech/allure-go/pkg/allure"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+// DefaultSentinels - errors failed operations are grouped by if error wraps them
+var DefaultSentinels = []error{
+	utils.ErrTxNotValid,
+	utils.ErrBatchResultNotFound,
+	context.DeadlineExceeded,
+	context.Canceled,
+}
+
+const (
+	p50 = 50
+	p90 = 90
+	p99 = 99
+)
+
+// Report struct for results of load
+// Missed - number of operations skipped because all workers were busy, see Config.Rate
+// Errors - number of failed operations by error: message of sentinel error wrapped by error (utils.ErrTxNotValid, ...)
+// or message of innermost error, it is ResponseError.Message for hlf proxy errors
+type Report struct {
+	Duration   time.Duration               `json:"duration"`
+	Total      int                         `json:"total"`
+	Failed     int                         `json:"failed"`
+	Missed     int                         `json:"missed,omitempty"`
+	Throughput float64                     `json:"throughput"`
+	Operations map[string]*OperationReport `json:"operations"`
+	Errors     map[string]int              `json:"errors,omitempty"`
+}
+
+// OperationReport struct for results of one operation of load
+type OperationReport struct {
+	Total  int            `json:"total"`
+	Failed int            `json:"failed"`
+	Missed int            `json:"missed,omitempty"`
+	P50    time.Duration  `json:"p50"`
+	P90    time.Duration  `json:"p90"`
+	P99    time.Duration  `json:"p99"`
+	Max    time.Duration  `json:"max"`
+	Errors map[string]int `json:"errors,omitempty"`
+}
+
+// String returns report in human-readable form
+func (r *Report) String() string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "duration %s, total %d, failed %d, missed %d, throughput %.2f op/s\n", r.Duration, r.Total, r.Failed, r.Missed, r.Throughput)
+
+	names := make([]string, 0, len(r.Operations))
+	for name := range r.Operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		op := r.Operations[name]
+		fmt.Fprintf(sb, "%s: total %d, failed %d, missed %d, p50 %s, p90 %s, p99 %s, max %s\n",
+			name, op.Total, op.Failed, op.Missed, op.P50, op.P90, op.P99, op.Max)
+		messages := make([]string, 0, len(op.Errors))
+		for msg := range op.Errors {
+			messages = append(messages, msg)
+		}
+		sort.Strings(messages)
+		for _, msg := range messages {
+			fmt.Fprintf(sb, "  %d x %s\n", op.Errors[msg], msg)
+		}
+	}
+
+	return sb.String()
+}
+
+// Attach adds report to current allure test as text and json attachments
+func (r *Report) Attach(t provider.T) {
+	t.WithNewAttachment("load report", allure.Text, []byte(r.String()))
+	if data, err := json.MarshalIndent(r, "", "  "); err == nil {
+		t.WithNewAttachment("load report json", allure.JSON, data)
+	}
+}
+
+type sample struct {
+	latencies []time.Duration
+	missed    int
+	errors    map[string]int
+}
+
+type collector struct {
+	sentinels []error
+
+	mu      sync.Mutex
+	samples map[string]*sample
+}
+
+func newCollector(sentinels ...error) *collector {
+	return &collector{
+		sentinels: append(append([]error{}, DefaultSentinels...), sentinels...),
+		samples:   make(map[string]*sample),
+	}
+}
+
+// run makes operation of job and adds its result
+func (c *collector) run(ctx context.Context, j job) {
+	started := time.Now()
+	err := j.op.Op(ctx, j.from, j.to)
+	c.add(j.op.Name, time.Since(started), err)
+}
+
+func (c *collector) add(name string, latency time.Duration, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.sample(name)
+	s.latencies = append(s.latencies, latency)
+	if err != nil {
+		s.errors[c.errorKey(err)]++
+	}
+}
+
+func (c *collector) miss(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sample(name).missed++
+}
+
+func (c *collector) sample(name string) *sample {
+	s, ok := c.samples[name]
+	if !ok {
+		s = &sample{errors: make(map[string]int)}
+		c.samples[name] = s
+	}
+	return s
+}
+
+// errorKey returns message errors are grouped by: message of first sentinel wrapped by err or message of innermost error,
+// so messages with transaction ids or other details of wrapping errors go to the same group
+func (c *collector) errorKey(err error) string {
+	for _, sentinel := range c.sentinels {
+		if errors.Is(err, sentinel) {
+			return sentinel.Error()
+		}
+	}
+	for {
+		unwrapped := errors.Unwrap(err)
+		if unwrapped == nil {
+			return err.Error()
+		}
+		err = unwrapped
+	}
+}
+
+func (c *collector) report(duration time.Duration) *Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r := &Report{
+		Duration:   duration,
+		Operations: make(map[string]*OperationReport, len(c.samples)),
+		Errors:     make(map[string]int),
+	}
+
+	for name, s := range c.samples {
+		sort.Slice(s.latencies, func(i, j int) bool { return s.latencies[i] < s.latencies[j] })
+		op := &OperationReport{
+			Total:  len(s.latencies),
+			P50:    percentile(s.latencies, p50),
+			P90:    percentile(s.latencies, p90),
+			P99:    percentile(s.latencies, p99),
+			Max:    percentile(s.latencies, 100), //nolint:gomnd
+			Missed: s.missed,
+			Errors: s.errors,
+		}
+		for msg, n := range s.errors {
+			op.Failed += n
+			r.Errors[msg] += n
+		}
+		r.Operations[name] = op
+		r.Total += op.Total
+		r.Failed += op.Failed
+		r.Missed += op.Missed
+	}
+
+	if duration > 0 {
+		r.Throughput = float64(r.Total) / duration.Seconds()
+	}
+
+	return r
+}
+
+// percentile returns latency of percentile p in sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := (len(sorted)*p+99)/100 - 1 //nolint:gomnd
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
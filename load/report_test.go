@@ -0,0 +1,84 @@
+package load
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	utils "github.com/anoideaopen/testnet-util"
+	"github.com/stretchr/testify/require"
+)
+
+var errUnknownMethod = errors.New("unknown method")
+
+func TestCollectorErrorKey(t *testing.T) {
+	c := newCollector(errUnknownMethod)
+
+	tests := []struct {
+		name string
+		err  error
+		key  string
+	}{
+		{name: "invalid tx", err: fmt.Errorf("%w: tx 1 validation code MVCC_READ_CONFLICT", utils.ErrTxNotValid), key: utils.ErrTxNotValid.Error()},
+		{name: "other invalid tx", err: fmt.Errorf("%w: tx 2 chaincode status 500", utils.ErrTxNotValid), key: utils.ErrTxNotValid.Error()},
+		{name: "wrapped timeout", err: fmt.Errorf("invoke: %w", context.DeadlineExceeded), key: context.DeadlineExceeded.Error()},
+		{name: "config sentinel", err: fmt.Errorf("%w: fiat emit", errUnknownMethod), key: errUnknownMethod.Error()},
+		{name: "chaincode message", err: errors.New("insufficient funds"), key: "insufficient funds"},
+		{name: "innermost message", err: fmt.Errorf("tx 3: %w", errors.New("insufficient funds")), key: "insufficient funds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.key, c.errorKey(tt.err))
+		})
+	}
+}
+
+func TestCollectorReport(t *testing.T) {
+	c := newCollector()
+	for i := 1; i <= 100; i++ {
+		var err error
+		if i%10 == 0 {
+			err = fmt.Errorf("%w: tx %d", utils.ErrTxNotValid, i)
+		}
+		c.add("transfer", time.Duration(i)*time.Millisecond, err)
+	}
+	c.add("emit", time.Millisecond, nil)
+	c.miss("emit")
+
+	r := c.report(time.Second)
+	require.Equal(t, 101, r.Total)
+	require.Equal(t, 10, r.Failed)
+	require.Equal(t, 1, r.Missed)
+	require.InDelta(t, 101.0, r.Throughput, 0.001)
+	require.Equal(t, map[string]int{utils.ErrTxNotValid.Error(): 10}, r.Errors)
+
+	transfer := r.Operations["transfer"]
+	require.Equal(t, 50*time.Millisecond, transfer.P50)
+	require.Equal(t, 90*time.Millisecond, transfer.P90)
+	require.Equal(t, 99*time.Millisecond, transfer.P99)
+	require.Equal(t, 100*time.Millisecond, transfer.Max)
+	require.Equal(t, 1, r.Operations["emit"].Missed)
+	require.Contains(t, r.String(), "10 x "+utils.ErrTxNotValid.Error())
+}
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		name     string
+		sorted   []time.Duration
+		p        int
+		expected time.Duration
+	}{
+		{name: "empty", p: 50},
+		{name: "single", sorted: []time.Duration{3}, p: 99, expected: 3},
+		{name: "median of two", sorted: []time.Duration{1, 2}, p: 50, expected: 1},
+		{name: "max", sorted: []time.Duration{1, 2, 3}, p: 100, expected: 3},
+		{name: "zero", sorted: []time.Duration{1, 2, 3}, p: 0, expected: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, percentile(tt.sorted, tt.p))
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+// BalanceLockRequest struct for request of locking or unlocking balance
+// ID - id of lock, generated by chaincode on lock if empty, required on unlock
+// Token - ticker of allowed balance, empty for token balance
+type BalanceLockRequest struct {
+	ID      string   `json:"id,omitempty"`
+	Address string   `json:"address"`
+	Token   string   `json:"token,omitempty"`
+	Amount  string   `json:"amount"`
+	Reason  string   `json:"reason,omitempty"`
+	Docs    []string `json:"docs,omitempty"`
+	Payload []byte   `json:"payload,omitempty"`
+}
+
+// BalanceLock struct for lock of balance returned by chaincode
+type BalanceLock struct {
+	ID            string   `json:"id"`
+	Address       string   `json:"address"`
+	Token         string   `json:"token"`
+	InitAmount    string   `json:"initAmount"`
+	CurrentAmount string   `json:"currentAmount"`
+	Reason        string   `json:"reason"`
+	Docs          []string `json:"docs"`
+	Payload       []byte   `json:"payload"`
+}
+
+// LockTokenBalance signs by issuer and invokes locking amount of token balance, returns id of lock together with response
+func LockTokenBalance(hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string, req BalanceLockRequest) (string, *Response, error) {
+	return lockBalance(hlfProxy, issuer, channel, chaincode, "lockTokenBalance", req)
+}
+
+// UnlockTokenBalance signs by issuer and invokes unlocking amount of token balance locked by lock req.ID
+func UnlockTokenBalance(hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string, req BalanceLockRequest) (*Response, error) {
+	return invokeBalanceLock(hlfProxy, issuer, channel, chaincode, "unlockTokenBalance", req)
+}
+
+// LockAllowedBalance signs by issuer and invokes locking amount of allowed balance of req.Token,
+// returns id of lock together with response
+func LockAllowedBalance(hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string, req BalanceLockRequest) (string, *Response, error) {
+	return lockBalance(hlfProxy, issuer, channel, chaincode, "lockAllowedBalance", req)
+}
+
+// UnlockAllowedBalance signs by issuer and invokes unlocking amount of allowed balance locked by lock req.ID
+func UnlockAllowedBalance(hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string, req BalanceLockRequest) (*Response, error) {
+	return invokeBalanceLock(hlfProxy, issuer, channel, chaincode, "unlockAllowedBalance", req)
+}
+
+// GetLockedTokenBalance returns lock of token balance by its id
+func GetLockedTokenBalance(hlfProxy ChaincodeClient, channel string, lockID string) (*BalanceLock, error) {
+	return queryBalanceLock(hlfProxy, channel, "getLockedTokenBalance", lockID)
+}
+
+// GetLockedAllowedBalance returns lock of allowed balance by its id
+func GetLockedAllowedBalance(hlfProxy ChaincodeClient, channel string, lockID string) (*BalanceLock, error) {
+	return queryBalanceLock(hlfProxy, channel, "getLockedAllowedBalance", lockID)
+}
+
+// LockTokenBalanceAndCheck locks amount of token balance of req.Address and checks that available balance
+// is decreased and locked balance is increased by amount, returns id of lock together with response
+func LockTokenBalanceAndCheck(t provider.T, hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string, req BalanceLockRequest) (string, *Response) {
+	res := balanceLockAndCheck(t, hlfProxy, issuer, channel, chaincode, "lockTokenBalance", "", req, 1)
+	return lockID(req, res), res
+}
+
+// UnlockTokenBalanceAndCheck unlocks amount of token balance of req.Address and checks that available balance
+// is increased and locked balance is decreased by amount
+func UnlockTokenBalanceAndCheck(t provider.T, hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string, req BalanceLockRequest) *Response {
+	return balanceLockAndCheck(t, hlfProxy, issuer, channel, chaincode, "unlockTokenBalance", "", req, -1)
+}
+
+// LockAllowedBalanceAndCheck locks amount of allowed balance of req.Token of req.Address and checks that available
+// allowed balance is decreased and locked allowed balance is increased by amount, returns id of lock together with response
+func LockAllowedBalanceAndCheck(t provider.T, hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string, req BalanceLockRequest) (string, *Response) {
+	res := balanceLockAndCheck(t, hlfProxy, issuer, channel, chaincode, "lockAllowedBalance", req.Token, req, 1)
+	return lockID(req, res), res
+}
+
+// UnlockAllowedBalanceAndCheck unlocks amount of allowed balance of req.Token of req.Address and checks that available
+// allowed balance is increased and locked allowed balance is decreased by amount
+func UnlockAllowedBalanceAndCheck(t provider.T, hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string, req BalanceLockRequest) *Response {
+	return balanceLockAndCheck(t, hlfProxy, issuer, channel, chaincode, "unlockAllowedBalance", req.Token, req, -1)
+}
+
+// CheckBalanceWithLockedEqual checks that available token balance of userAddressBase58Check is equal to available
+// and locked token balance is equal to locked
+func CheckBalanceWithLockedEqual(t provider.T, hlfProxy ChaincodeClient, userAddressBase58Check string, channel string, available string, locked string) {
+	t.WithNewStep("Checking that balance equal "+available+" and locked balance equal "+locked, func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		checkBalanceWithLocked(sCtx, hlfProxy, userAddressBase58Check, channel, "", available, locked)
+	})
+}
+
+// CheckAllowedBalanceWithLockedEqual checks that available allowed balance of token of userAddressBase58Check
+// is equal to available and locked allowed balance is equal to locked
+func CheckAllowedBalanceWithLockedEqual(
+	t provider.T,
+	hlfProxy ChaincodeClient,
+	userAddressBase58Check string,
+	channel string,
+	token string,
+	available string,
+	locked string,
+) {
+	t.WithNewStep("Checking that allowed balance of "+token+" equal "+available+" and locked allowed balance equal "+locked, func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		checkBalanceWithLocked(sCtx, hlfProxy, userAddressBase58Check, channel, token, available, locked)
+	})
+}
+
+func checkBalanceWithLocked(sCtx provider.StepCtx, hlfProxy ChaincodeClient, address string, channel string, token string, available string, locked string) {
+	gotAvailable, gotLocked, err := getBalanceWithLocked(hlfProxy, address, channel, token)
+	sCtx.Require().NoError(err)
+	sCtx.Require().Equal(available, gotAvailable.String(), "available balance")
+	sCtx.Require().Equal(locked, gotLocked.String(), "locked balance")
+}
+
+// getBalanceWithLocked returns available and locked token balance if token is empty and allowed balance of token otherwise
+func getBalanceWithLocked(hlfProxy ChaincodeClient, address string, channel string, token string) (*big.Int, *big.Int, error) {
+	balanceFcn, lockedFcn, args := "balanceOf", "lockedBalanceOf", []string{address}
+	if token != "" {
+		balanceFcn, lockedFcn, args = "allowedBalanceOf", "lockedAllowedBalanceOf", []string{address, token}
+	}
+
+	available, err := QueryAmount(hlfProxy, channel, balanceFcn, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	locked, err := QueryAmount(hlfProxy, channel, lockedFcn, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return available, locked, nil
+}
+
+func balanceLockAndCheck(
+	t provider.T,
+	hlfProxy ChaincodeClient,
+	issuer Issuer,
+	channel string,
+	chaincode string,
+	fcn string,
+	token string,
+	req BalanceLockRequest,
+	sign int64,
+) *Response {
+	var res *Response
+	t.WithNewStep(fcn+" "+req.Amount+" of "+req.Address, func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		amount := parseAmount(sCtx, req.Amount)
+		amount.Mul(amount, big.NewInt(sign))
+
+		available, locked, err := getBalanceWithLocked(hlfProxy, req.Address, channel, token)
+		sCtx.Require().NoError(err)
+
+		res, err = invokeBalanceLock(hlfProxy, issuer, channel, chaincode, fcn, req)
+		sCtx.Require().NoError(err)
+		time.Sleep(BatchTransactionTimeout)
+
+		available.Sub(available, amount)
+		locked.Add(locked, amount)
+		checkBalanceWithLocked(sCtx, hlfProxy, req.Address, channel, token, available.String(), locked.String())
+	})
+	return res
+}
+
+func lockBalance(hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string, fcn string, req BalanceLockRequest) (string, *Response, error) {
+	res, err := invokeBalanceLock(hlfProxy, issuer, channel, chaincode, fcn, req)
+	if err != nil {
+		return "", res, err
+	}
+	return lockID(req, res), res, nil
+}
+
+// lockID returns id of lock made by request: req.ID if it is set, id of lock transaction otherwise as chaincode generates it
+func lockID(req BalanceLockRequest, res *Response) string {
+	if req.ID != "" || res == nil {
+		return req.ID
+	}
+	return res.TransactionID
+}
+
+func invokeBalanceLock(hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string, fcn string, req BalanceLockRequest) (*Response, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	return invokeByIssuer(hlfProxy, issuer, channel, chaincode, fcn, string(data))
+}
+
+func queryBalanceLock(hlfProxy ChaincodeClient, channel string, fcn string, lockID string) (*BalanceLock, error) {
+	resp, err := hlfProxy.QueryContext(context.Background(), channel, fcn, lockID)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &BalanceLock{}
+	if err = json.Unmarshal(resp.Payload, lock); err != nil {
+		return nil, fmt.Errorf("json unmarshal balance lock: %w", err)
+	}
+	return lock, nil
+}
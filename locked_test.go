@@ -0,0 +1,42 @@
+package utils_test
+
+import (
+	"context"
+	"testing"
+
+	utils "github.com/anoideaopen/testnet-util"
+	"github.com/anoideaopen/testnet-util/fake"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockBalanceID(t *testing.T) {
+	_, issuer := newFakeClient(t)
+	ok := func(context.Context, []string) ([]byte, error) { return nil, nil }
+	client := fake.NewClient().
+		Handle("cc", "lockTokenBalance", ok).
+		Handle("cc", "lockAllowedBalance", ok)
+
+	type lockFunc func(utils.ChaincodeClient, utils.Issuer, string, string, utils.BalanceLockRequest) (string, *utils.Response, error)
+	tests := []struct {
+		name string
+		lock lockFunc
+		id   string
+	}{
+		{name: "token balance generated id", lock: utils.LockTokenBalance},
+		{name: "token balance given id", lock: utils.LockTokenBalance, id: "lock-1"},
+		{name: "allowed balance generated id", lock: utils.LockAllowedBalance},
+		{name: "allowed balance given id", lock: utils.LockAllowedBalance, id: "lock-2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, res, err := tt.lock(client, issuer, "cc", "cc", utils.BalanceLockRequest{ID: tt.id, Address: "address", Amount: "1"})
+			require.NoError(t, err)
+			require.NotEmpty(t, res.TransactionID)
+			if tt.id == "" {
+				require.Equal(t, res.TransactionID, id)
+				return
+			}
+			require.Equal(t, tt.id, id)
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/ozontech/allure-go/pkg/allure"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"golang.org/x/crypto/ed25519"
+)
+
+// LogLevel - level of log record, values are the same as in log/slog
+type LogLevel int
+
+// Log levels
+const (
+	LevelDebug LogLevel = -4
+	LevelInfo  LogLevel = 0
+	LevelWarn  LogLevel = 4
+	LevelError LogLevel = 8
+)
+
+// LogLevelEnv - level of default logger: debug, info, warn or error
+const LogLevelEnv = "LOG_LEVEL"
+
+// privateKeyPayloadLen - length of private key ed25519 in base58 check without version byte
+const privateKeyPayloadLen = ed25519.PrivateKeySize - 1
+
+// Logger - structured logger with key-value args, *slog.Logger satisfies it
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+var (
+	defaultLoggerMu sync.RWMutex
+	defaultLogger   Logger = NewTextLogger(os.Stdout, ParseLogLevel(GetEnv(LogLevelEnv, "info")))
+)
+
+// DefaultLogger returns logger used by HlfProxyService without logger and by Invoke and Query
+func DefaultLogger() Logger {
+	defaultLoggerMu.RLock()
+	defer defaultLoggerMu.RUnlock()
+	return defaultLogger
+}
+
+// SetDefaultLogger sets logger returned by DefaultLogger
+func SetDefaultLogger(logger Logger) {
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	defaultLogger = logger
+}
+
+// ParseLogLevel returns level by name, LevelInfo if name is unknown
+func ParseLogLevel(name string) LogLevel {
+	switch strings.ToLower(name) {
+	case "debug":
+		return LevelDebug
+	case "warn":
+		return LevelWarn
+	case "error":
+		return LevelError
+	default:
+		return LevelInfo
+	}
+}
+
+// String returns name of level in upper case
+func (l LogLevel) String() string {
+	switch {
+	case l < LevelInfo:
+		return "DEBUG"
+	case l < LevelWarn:
+		return "INFO"
+	case l < LevelError:
+		return "WARN"
+	default:
+		return "ERROR"
+	}
+}
+
+// TextLogger - logger writing records with level not lower than level as text lines: time level msg key=value...
+type TextLogger struct {
+	mu    sync.Mutex
+	w     io.Writer
+	level LogLevel
+}
+
+// NewTextLogger - create new instance of TextLogger
+func NewTextLogger(w io.Writer, level LogLevel) *TextLogger {
+	return &TextLogger{w: w, level: level}
+}
+
+// Debug logs record with LevelDebug
+func (l *TextLogger) Debug(msg string, args ...any) { l.log(LevelDebug, msg, args) }
+
+// Info logs record with LevelInfo
+func (l *TextLogger) Info(msg string, args ...any) { l.log(LevelInfo, msg, args) }
+
+// Warn logs record with LevelWarn
+func (l *TextLogger) Warn(msg string, args ...any) { l.log(LevelWarn, msg, args) }
+
+// Error logs record with LevelError
+func (l *TextLogger) Error(msg string, args ...any) { l.log(LevelError, msg, args) }
+
+func (l *TextLogger) log(level LogLevel, msg string, args []any) {
+	if level < l.level {
+		return
+	}
+
+	line := time.Now().Format(time.RFC3339) + " " + level.String() + " " + msg
+	if attrs := formatAttrs(args, " ", "="); attrs != "" {
+		line += " " + attrs
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = io.WriteString(l.w, line+"\n")
+}
+
+// AllureLogger - logger attaching every record to allure step as text attachment named by message
+type AllureLogger struct {
+	sCtx provider.StepCtx
+}
+
+// NewAllureLogger - create logger attaching records to step instead of writing them to stdout
+func NewAllureLogger(sCtx provider.StepCtx) *AllureLogger {
+	return &AllureLogger{sCtx: sCtx}
+}
+
+// Debug attaches record with LevelDebug
+func (l *AllureLogger) Debug(msg string, args ...any) { l.log(LevelDebug, msg, args) }
+
+// Info attaches record with LevelInfo
+func (l *AllureLogger) Info(msg string, args ...any) { l.log(LevelInfo, msg, args) }
+
+// Warn attaches record with LevelWarn
+func (l *AllureLogger) Warn(msg string, args ...any) { l.log(LevelWarn, msg, args) }
+
+// Error attaches record with LevelError
+func (l *AllureLogger) Error(msg string, args ...any) { l.log(LevelError, msg, args) }
+
+func (l *AllureLogger) log(level LogLevel, msg string, args []any) {
+	l.sCtx.WithNewAttachment(level.String()+" "+msg, allure.Text, []byte(formatAttrs(args, "\n", ": ")))
+}
+
+// formatAttrs formats key-value args, value without key is logged with key !BADKEY as in log/slog
+func formatAttrs(args []any, sep string, kvSep string) string {
+	parts := make([]string, 0, (len(args)+1)/2) //nolint:gomnd
+	for i := 0; i < len(args); {
+		key, ok := args[i].(string)
+		if !ok || i+1 == len(args) {
+			parts = append(parts, "!BADKEY"+kvSep+formatValue(args[i]))
+			i++
+			continue
+		}
+		parts = append(parts, key+kvSep+formatValue(args[i+1]))
+		i += 2
+	}
+	return strings.Join(parts, sep)
+}
+
+func formatValue(v any) string {
+	var s string
+	switch val := v.(type) {
+	case string:
+		s = val
+	case []byte:
+		s = string(val)
+	case error:
+		s = val.Error()
+	default:
+		s = fmt.Sprint(val)
+	}
+	if strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// RedactArgs returns copy of args with private keys hidden and signatures and public keys shortened
+func RedactArgs(args []string) []string {
+	res := make([]string, len(args))
+	for i, arg := range args {
+		res[i] = RedactArg(arg)
+	}
+	return res
+}
+
+// RedactArg returns arg with private key in base58 check hidden, ed25519 signature and public key in base58 shortened
+func RedactArg(arg string) string {
+	if payload, _, err := base58.CheckDecode(arg); err == nil && len(payload) == privateKeyPayloadLen {
+		return "<redacted>"
+	}
+	if n := len(base58.Decode(arg)); n == ed25519.SignatureSize || n == ed25519.PublicKeySize {
+		return shorten(arg)
+	}
+	return arg
+}
+
+// shorten returns first and last symbols of s
+func shorten(s string) string {
+	const keep = 4
+	if len(s) <= 2*keep {
+		return s
+	}
+	return s[:keep] + "..." + s[len(s)-keep:]
+}
@@ -0,0 +1,127 @@
+package utils_test
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	utils "github.com/anoideaopen/testnet-util"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	allure "github.com/ozontech/allure-go/pkg/framework/runner"
+	"github.com/stretchr/testify/require"
+)
+
+// signedTransfer returns private key in base58 check and transfer args signed by key derived from seed
+func signedTransfer(t *testing.T) (string, []string) {
+	t.Helper()
+	privateKey, publicKey, err := utils.DerivePrivateAndPublicKey([]byte("logger"), "alice")
+	require.NoError(t, err)
+	privateKeyBase58Check, err := utils.ConvertPrivateKeyToBase58Check(privateKey)
+	require.NoError(t, err)
+	signed, err := utils.Sign(privateKey, publicKey, "fiat", "fiat", "transfer", []string{"address", "100", "ref transfer"})
+	require.NoError(t, err)
+	return privateKeyBase58Check, signed
+}
+
+func TestRedactArg(t *testing.T) {
+	privateKey, signed := signedTransfer(t)
+	publicKey, signature := signed[len(signed)-2], signed[len(signed)-1]
+
+	tests := []struct {
+		name string
+		arg  string
+		want string
+	}{
+		{name: "private key", arg: privateKey, want: "<redacted>"},
+		{name: "signature", arg: signature, want: signature[:4] + "..." + signature[len(signature)-4:]},
+		{name: "public key", arg: publicKey, want: publicKey[:4] + "..." + publicKey[len(publicKey)-4:]},
+		{name: "amount", arg: "100", want: "100"},
+		{name: "text", arg: "ref transfer", want: "ref transfer"},
+		{name: "empty", arg: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, utils.RedactArg(tt.arg))
+		})
+	}
+}
+
+func TestRedactArgs(t *testing.T) {
+	privateKey, signed := signedTransfer(t)
+	args := append([]string{privateKey}, signed...)
+
+	redacted := utils.RedactArgs(args)
+	require.Len(t, redacted, len(args))
+	require.Equal(t, privateKey, args[0], "args are not changed")
+	require.Equal(t, "<redacted>", redacted[0])
+	require.Equal(t, args[1:len(args)-2], redacted[1:len(args)-2], "normal args are kept")
+	require.NotEqual(t, args[len(args)-2], redacted[len(args)-2])
+	require.NotEqual(t, args[len(args)-1], redacted[len(args)-1])
+}
+
+// newProxyServer returns hlf proxy server answering every request with valid transaction
+func newProxyServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"transactionId":"tx1","blockNumber":1}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestProxyLogRedacted(t *testing.T) {
+	privateKey, signed := signedTransfer(t)
+	args := append([]string{privateKey}, signed...)
+	server := newProxyServer(t)
+
+	var buf bytes.Buffer
+	hlfProxy := utils.NewHlfProxyService(server.URL, "", utils.WithLogger(utils.NewTextLogger(&buf, utils.LevelDebug)))
+	_, err := hlfProxy.InvokeContext(context.Background(), "fiat", "transfer", args...)
+	require.NoError(t, err)
+
+	logged := buf.String()
+	require.Contains(t, logged, "<redacted>")
+	require.Contains(t, logged, utils.RedactArg(signed[len(signed)-1]))
+	for _, secret := range []string{privateKey, signed[len(signed)-2], signed[len(signed)-1]} {
+		require.NotContains(t, logged, secret)
+	}
+}
+
+func TestProxyAttachmentsRedacted(t *testing.T) {
+	output := t.TempDir()
+	t.Setenv("ALLURE_OUTPUT_PATH", output)
+	privateKey, signed := signedTransfer(t)
+	args := append([]string{privateKey}, signed...)
+	server := newProxyServer(t)
+
+	allure.Run(t, "redacted attachments", func(pt provider.T) {
+		pt.WithNewStep("Transfer", func(sCtx provider.StepCtx) {
+			hlfProxy := utils.NewHlfProxyService(server.URL, "", utils.WithLogger(utils.NewAllureLogger(sCtx)), utils.WithStep(sCtx))
+			_, err := hlfProxy.InvokeContext(context.Background(), "fiat", "transfer", args...)
+			sCtx.Require().NoError(err)
+		})
+	})
+	require.False(t, t.Failed())
+
+	var attached strings.Builder
+	err := filepath.WalkDir(output, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		attached.Write(data)
+		return err
+	})
+	require.NoError(t, err)
+
+	require.Contains(t, attached.String(), "<redacted>")
+	for _, secret := range []string{privateKey, signed[len(signed)-2], signed[len(signed)-1]} {
+		require.NotContains(t, attached.String(), secret)
+	}
+}
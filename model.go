@@ -5,6 +5,7 @@ type Request struct {
 	Args        [][]byte `json:"args"`
 	ChaincodeID string   `json:"chaincodeId"`
 	Fcn         string   `json:"fcn"`
+	Opts        *Options `json:"options,omitempty"`
 }
 
 // Response struct for response from hlf proxy
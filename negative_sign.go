@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"errors"
+
+	"github.com/btcsuite/btcutil/base58"
+	"golang.org/x/crypto/ed25519"
+)
+
+// Names of negative cases returned by NegativeSignCases
+const (
+	CaseCorruptedSignature = "corrupted signature"
+	CaseSwappedPublicKey   = "swapped public key"
+	CaseReorderedArgs      = "reordered args"
+	CaseOtherChannel       = "signed for other channel"
+	CaseOtherChaincode     = "signed for other chaincode"
+	CaseReusedNonce        = "reused nonce"
+	CaseSignedWithOtherKey = "signed with other key"
+	CaseMissingSignature   = "missing signature"
+)
+
+// Positions in arguments signed by Sign, arguments of method go between signedChannelPos and nonce
+const (
+	negativeSignWrongSuffix = "-wrong"
+
+	signedChannelPos = 2
+	signedArgsPos    = 3
+	signedTailLen    = 3
+)
+
+// SignRequest struct for arguments of Sign
+// Nonce - nonce to sign with, new nonce is generated if empty
+type SignRequest struct {
+	PrivateKey ed25519.PrivateKey
+	PublicKey  ed25519.PublicKey
+	Channel    string
+	Chaincode  string
+	Method     string
+	Args       []string
+	Nonce      string
+}
+
+// SignedCase struct for labelled signed arguments used in table-driven negative tests
+type SignedCase struct {
+	Name string
+	Args []string
+}
+
+// Sign - sign arguments of request, see SignWithNonce
+func (r SignRequest) Sign() ([]string, error) {
+	return SignWithNonce(r.PrivateKey, r.PublicKey, r.Channel, r.Chaincode, r.Method, r.Args, r.Nonce)
+}
+
+// NegativeSignCases - sign request and return cases with tampered signed arguments, chaincode is expected to reject each of them.
+// usedNonce - nonce of already executed transaction of same signer, case CaseReusedNonce is skipped if empty
+func NegativeSignCases(req SignRequest, usedNonce string) ([]SignedCase, error) {
+	signed, err := req.Sign()
+	if err != nil {
+		return nil, err
+	}
+
+	otherPrivateKey, otherPublicKey, err := GeneratePrivateAndPublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	cases := []SignedCase{
+		{CaseCorruptedSignature, CorruptSignature(signed)},
+		{CaseSwappedPublicKey, ReplacePublicKey(signed, otherPublicKey)},
+		{CaseMissingSignature, signed[:len(signed)-1]},
+	}
+
+	if len(req.Args) > 1 && req.Args[0] != req.Args[1] {
+		cases = append(cases, SignedCase{CaseReorderedArgs, SwapSignedArgs(signed, 0, 1)})
+	}
+
+	otherChannel := req
+	otherChannel.Channel += negativeSignWrongSuffix
+	otherChaincode := req
+	otherChaincode.Chaincode += negativeSignWrongSuffix
+	otherKey := req
+	otherKey.PrivateKey, otherKey.PublicKey = otherPrivateKey, otherPublicKey
+	variants := []SignedCase{{Name: CaseOtherChannel}, {Name: CaseOtherChaincode}, {Name: CaseSignedWithOtherKey}}
+	requests := []SignRequest{otherChannel, otherChaincode, otherKey}
+
+	if usedNonce != "" {
+		reused := req
+		reused.Nonce = usedNonce
+		variants = append(variants, SignedCase{Name: CaseReusedNonce})
+		requests = append(requests, reused)
+	}
+
+	for i, r := range requests {
+		if variants[i].Args, err = r.Sign(); err != nil {
+			return nil, err
+		}
+	}
+
+	// channel and chaincode are sent as they are expected, only signature is made for other ones
+	variants[0].Args[signedChannelPos] = req.Channel
+	variants[1].Args[signedChannelPos-1] = req.Chaincode
+	variants[2].Args = ReplacePublicKey(variants[2].Args, req.PublicKey)
+
+	return append(cases, variants...), nil
+}
+
+// CorruptSignature returns copy of signed arguments with one byte of signature changed
+func CorruptSignature(signed []string) []string {
+	res := append([]string{}, signed...)
+	if len(res) == 0 {
+		return res
+	}
+	sig := base58.Decode(res[len(res)-1])
+	if len(sig) == 0 {
+		sig = make([]byte, ed25519.SignatureSize)
+	}
+	sig[0] ^= 0xff
+	res[len(res)-1] = base58.Encode(sig)
+	return res
+}
+
+// ReplacePublicKey returns copy of signed arguments with public key replaced, signature is left as is
+func ReplacePublicKey(signed []string, publicKey ed25519.PublicKey) []string {
+	res := append([]string{}, signed...)
+	if len(res) < signedTailLen {
+		return res
+	}
+	res[len(res)-2] = ConvertPublicKeyToBase58(publicKey)
+	return res
+}
+
+// SwapSignedArgs returns copy of signed arguments with method arguments i and j swapped, signature is left as is
+func SwapSignedArgs(signed []string, i int, j int) []string {
+	res := append([]string{}, signed...)
+	n := len(res) - signedArgsPos - signedTailLen
+	if i < 0 || j < 0 || i >= n || j >= n {
+		return res
+	}
+	res[signedArgsPos+i], res[signedArgsPos+j] = res[signedArgsPos+j], res[signedArgsPos+i]
+	return res
+}
+
+// GetSignedNonce returns nonce from arguments signed by Sign
+func GetSignedNonce(signed []string) (string, error) {
+	if len(signed) < signedArgsPos+signedTailLen {
+		return "", errors.New("not enough signed arguments")
+	}
+	return signed[len(signed)-signedTailLen], nil
+}
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/sha3"
+)
+
+// verifySigned checks signed arguments the way foundation does: signature of all arguments but signature
+// prefixed with method by public key going before signature
+func verifySigned(method string, signed []string) bool {
+	if len(signed) < signedArgsPos+signedTailLen {
+		return false
+	}
+	msg := sha3.Sum256([]byte(method + strings.Join(signed[:len(signed)-1], "")))
+	publicKey := base58.Decode(signed[len(signed)-2])
+	if len(publicKey) != ed25519.PublicKeySize {
+		return false
+	}
+	return ed25519.Verify(publicKey, msg[:], base58.Decode(signed[len(signed)-1]))
+}
+
+func TestNegativeSignCases(t *testing.T) {
+	privateKey, publicKey, err := DerivePrivateAndPublicKey([]byte("negative"), "user")
+	require.NoError(t, err)
+	req := SignRequest{
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+		Channel:    "fiat",
+		Chaincode:  "fiat",
+		Method:     "transfer",
+		Args:       []string{"to", "10", "ref"},
+	}
+
+	valid, err := req.Sign()
+	require.NoError(t, err)
+	require.True(t, verifySigned(req.Method, valid))
+
+	cases, err := NegativeSignCases(req, "1")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		validSignature bool
+	}{
+		{name: CaseCorruptedSignature},
+		{name: CaseSwappedPublicKey},
+		{name: CaseMissingSignature},
+		{name: CaseReorderedArgs},
+		{name: CaseOtherChannel},
+		{name: CaseOtherChaincode},
+		{name: CaseSignedWithOtherKey},
+		{name: CaseReusedNonce, validSignature: true},
+	}
+	require.Len(t, cases, len(tests))
+
+	byName := make(map[string][]string, len(cases))
+	for _, c := range cases {
+		byName[c.Name] = c.Args
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, ok := byName[tt.name]
+			require.True(t, ok)
+			require.Equal(t, tt.validSignature, verifySigned(req.Method, args))
+			if tt.name != CaseMissingSignature {
+				require.Equal(t, req.Channel, args[signedChannelPos], "channel is sent as expected")
+				require.Equal(t, req.Chaincode, args[signedChannelPos-1], "chaincode is sent as expected")
+			}
+		})
+	}
+
+	nonce, err := GetSignedNonce(byName[CaseReusedNonce])
+	require.NoError(t, err)
+	require.Equal(t, "1", nonce)
+	require.Equal(t, ConvertPublicKeyToBase58(publicKey), byName[CaseSignedWithOtherKey][len(valid)-2])
+}
+
+func TestNegativeSignCasesOptional(t *testing.T) {
+	privateKey, publicKey, err := DerivePrivateAndPublicKey([]byte("negative"), "user")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		args      []string
+		usedNonce string
+		skipped   []string
+	}{
+		{name: "one argument", args: []string{"a"}, usedNonce: "1", skipped: []string{CaseReorderedArgs}},
+		{name: "equal arguments", args: []string{"a", "a"}, usedNonce: "1", skipped: []string{CaseReorderedArgs}},
+		{name: "no used nonce", args: []string{"a", "b"}, skipped: []string{CaseReusedNonce}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := SignRequest{PrivateKey: privateKey, PublicKey: publicKey, Channel: "cc", Chaincode: "cc", Method: "m", Args: tt.args}
+			cases, err := NegativeSignCases(req, tt.usedNonce)
+			require.NoError(t, err)
+			for _, c := range cases {
+				require.NotContains(t, tt.skipped, c.Name)
+			}
+		})
+	}
+}
+
+func TestSwapSignedArgs(t *testing.T) {
+	signed := []string{"", "cc", "ch", "a", "b", "c", "nonce", "key", "sig"}
+
+	tests := []struct {
+		name     string
+		i        int
+		j        int
+		expected []string
+	}{
+		{name: "first and last", i: 0, j: 2, expected: []string{"", "cc", "ch", "c", "b", "a", "nonce", "key", "sig"}},
+		{name: "out of range", i: 0, j: 3, expected: signed},
+		{name: "negative", i: -1, j: 0, expected: signed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, SwapSignedArgs(signed, tt.i, tt.j))
+		})
+	}
+}
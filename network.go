@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// ChannelACL - registry key of acl channel
+	ChannelACL = "acl"
+	// ChannelFiat - registry key of fiat token channel
+	ChannelFiat = "fiat"
+	// ChannelCC - registry key of currency token channel
+	ChannelCC = "cc"
+	// ChannelInv - registry key of inv channel
+	ChannelInv = "inv"
+)
+
+// Channel describes channel on stand, chaincode installed in it and token issued by chaincode
+type Channel struct {
+	// Name - channel name, used as chaincodeId in hlf proxy requests
+	Name string `json:"name"`
+	// Chaincode - chaincode name, used in signed message
+	Chaincode string `json:"chaincode"`
+	// Ticker - token ticker in upper case, example FIAT, used in swaps and allowed balances
+	Ticker string `json:"ticker,omitempty"`
+	// IssuerPrivateKey - issuer private key ed25519 in base58 check
+	IssuerPrivateKey string `json:"issuerPrivateKey,omitempty"`
+}
+
+// Network struct describes channels deployed on stand
+// Channels - registry key (ChannelACL, ChannelFiat, ...) to channel
+type Network struct {
+	Channels map[string]Channel `json:"channels"`
+}
+
+// DefaultNetwork returns network where every channel is named as its registry key
+func DefaultNetwork() Network {
+	return Network{
+		Channels: map[string]Channel{
+			ChannelACL:  {Name: "acl", Chaincode: "acl"},
+			ChannelFiat: {Name: "fiat", Chaincode: "fiat", Ticker: "FIAT"},
+			ChannelCC:   {Name: "cc", Chaincode: "cc", Ticker: "CC"},
+			ChannelInv:  {Name: "inv", Chaincode: "inv"},
+		},
+	}
+}
+
+// Channel returns channel registered in network by key.
+// If key is not registered channel and chaincode are considered to be named as key and ticker as key in upper case
+func (n Network) Channel(key string) Channel {
+	if channel, ok := n.Channels[key]; ok {
+		return channel
+	}
+	return Channel{Name: key, Chaincode: key, Ticker: strings.ToUpper(key)}
+}
+
+// WithChannel returns copy of network with channel registered by key
+func (n Network) WithChannel(key string, channel Channel) Network {
+	channels := make(map[string]Channel, len(n.Channels)+1)
+	for k, v := range n.Channels {
+		channels[k] = v
+	}
+	channels[key] = channel
+	return Network{Channels: channels}
+}
+
+// Issuer returns issuer of token in channel registered by key
+func (n Network) Issuer(key string) (Issuer, error) {
+	channel := n.Channel(key)
+	if channel.IssuerPrivateKey == "" {
+		return Issuer{}, fmt.Errorf("issuer of channel %s is not set", key)
+	}
+	return NewIssuer(channel.IssuerPrivateKey)
+}
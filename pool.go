@@ -0,0 +1,258 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+// PoolTakeTimeout - time to wait for free user in pool
+const PoolTakeTimeout = time.Minute
+
+// PoolToken struct for token users of pool are funded with
+// Channel - registry key of token channel in network
+// Issuer - issuer of token emitting Amount to every user
+type PoolToken struct {
+	Channel string
+	Issuer  Issuer
+	Amount  string
+}
+
+// UserPoolConfig struct for configuration of UserPool
+// Tokens - tokens every user is funded with, users are not funded if empty
+// Source - source of batch results emissions are waited by, required if Tokens are set
+// Size - number of users created and funded in one round
+type UserPoolConfig struct {
+	Tokens []PoolToken
+	Source BatchResultSource
+	Size   int
+}
+
+// UserPool struct for pool of users pre-created and pre-funded in background and handed out to parallel tests exclusively.
+// Users are created in rounds of Size: addUser transactions of a round are committed first, then emissions of every token
+// are sent and user is handed out only after all its emissions are executed in batch without error
+type UserPool struct {
+	hlfProxy ChaincodeClient
+	network  Network
+	cfg      UserPoolConfig
+
+	users  chan User
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu      sync.Mutex
+	created []User
+	taken   []User
+	lastErr error
+}
+
+// NewUserPool - create pool and start filling it in background, Close or Cleanup must be called to stop filling
+func NewUserPool(hlfProxy ChaincodeClient, network Network, cfg UserPoolConfig) (*UserPool, error) {
+	if len(cfg.Tokens) != 0 && cfg.Source == nil {
+		return nil, errors.New("user pool: batch result source is required to fund users")
+	}
+	if cfg.Size <= 0 {
+		cfg.Size = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &UserPool{
+		hlfProxy: hlfProxy,
+		network:  network,
+		cfg:      cfg,
+		users:    make(chan User, cfg.Size),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+
+	go p.fill(ctx)
+
+	return p, nil
+}
+
+// Take returns user not handed out before, waits until user is created and funded
+func (p *UserPool) Take(t provider.T) User {
+	var user User
+	t.WithNewStep("Take user from pool", func(sCtx provider.StepCtx) {
+		var err error
+		user, err = p.TakeContext(context.Background())
+		sCtx.Require().NoError(err)
+		sCtx.WithNewParameters("address", user.UserAddressBase58Check)
+	})
+	return user
+}
+
+// TakeContext returns user not handed out before, waits until user is created and funded or PoolTakeTimeout expires
+func (p *UserPool) TakeContext(ctx context.Context) (User, error) {
+	ctx, cancel := context.WithTimeout(ctx, PoolTakeTimeout)
+	defer cancel()
+
+	select {
+	case user, ok := <-p.users:
+		if !ok {
+			return User{}, errors.New("user pool is closed")
+		}
+		p.mu.Lock()
+		p.taken = append(p.taken, user)
+		p.mu.Unlock()
+		return user, nil
+	case <-ctx.Done():
+		return User{}, fmt.Errorf("take user from pool: %w, last error: %v", ctx.Err(), p.Err())
+	}
+}
+
+// Taken returns users handed out by pool
+func (p *UserPool) Taken() []User {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	taken := make([]User, len(p.taken))
+	copy(taken, p.taken)
+	return taken
+}
+
+// Created returns users created by pool including users funded partially and users not handed out
+func (p *UserPool) Created() []User {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	created := make([]User, len(p.created))
+	copy(created, p.created)
+	return created
+}
+
+// Err returns last error of filling pool
+func (p *UserPool) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lastErr
+}
+
+// Close stops filling pool, users not handed out are dropped
+func (p *UserPool) Close() {
+	p.cancel()
+	<-p.done
+}
+
+// Cleanup stops filling pool and calls cleanup for every user created by pool, example transfer of rest of tokens back to issuer.
+// Returns first error of cleanup, cleanup is called for all users anyway
+func (p *UserPool) Cleanup(ctx context.Context, cleanup func(ctx context.Context, user User) error) error {
+	p.Close()
+
+	var firstErr error
+	for _, user := range p.Created() {
+		if err := cleanup(ctx, user); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("cleanup user %s: %w", user.UserAddressBase58Check, err)
+		}
+	}
+	return firstErr
+}
+
+func (p *UserPool) fill(ctx context.Context) {
+	defer close(p.done)
+	defer close(p.users)
+
+	for {
+		users, err := p.createRound(ctx)
+		if err != nil {
+			p.mu.Lock()
+			p.lastErr = err
+			p.mu.Unlock()
+		}
+
+		for _, user := range users {
+			select {
+			case p.users <- user:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		retry := time.Duration(0)
+		if err != nil {
+			retry = BatchTransactionTimeout
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+	}
+}
+
+// createRound creates and funds round of users, returns users ready for tests even if some of them failed
+func (p *UserPool) createRound(ctx context.Context) ([]User, error) {
+	var lastErr error
+
+	users := make([]User, 0, p.cfg.Size)
+	for i := 0; i < p.cfg.Size; i++ {
+		privateKey, _, err := GeneratePrivateAndPublicKey()
+		if err != nil {
+			return nil, err
+		}
+		user, err := NewUser(privateKey)
+		if err != nil {
+			return nil, err
+		}
+		// addUser is not batched, invoke returns error unless transaction is committed as VALID
+		if _, err = RegisterUserContext(ctx, p.hlfProxy, p.network, user); err != nil {
+			lastErr = err
+			continue
+		}
+		users = append(users, user)
+	}
+
+	p.mu.Lock()
+	p.created = append(p.created, users...)
+	p.mu.Unlock()
+
+	funded := users
+	for _, token := range p.cfg.Tokens {
+		var err error
+		if funded, err = p.fund(ctx, funded, token); err != nil {
+			lastErr = err
+		}
+	}
+
+	return funded, lastErr
+}
+
+// fund emits amount of token to users and returns users emission is executed in batch for without error
+func (p *UserPool) fund(ctx context.Context, users []User, token PoolToken) ([]User, error) {
+	var lastErr error
+
+	channel := p.network.Channel(token.Channel)
+	txIDs := make(map[string]string, len(users))
+	for _, user := range users {
+		res, err := EmitContext(ctx, p.hlfProxy, user.UserAddressBase58Check, token.Issuer, channel.Name, channel.Chaincode, token.Amount)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		txIDs[user.UserAddressBase58Check] = res.TransactionID
+	}
+
+	funded := make([]User, 0, len(txIDs))
+	for _, user := range users {
+		txID, ok := txIDs[user.UserAddressBase58Check]
+		if !ok {
+			continue
+		}
+		result, err := WaitBatchResult(ctx, p.cfg.Source, channel.Name, txID)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if result.Error != nil {
+			lastErr = fmt.Errorf("emit tx %s failed in batch: %s", txID, result.Error.Error)
+			continue
+		}
+		funded = append(funded, user)
+	}
+
+	return funded, lastErr
+}
@@ -0,0 +1,103 @@
+package utils_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	utils "github.com/anoideaopen/testnet-util"
+	"github.com/anoideaopen/testnet-util/fake"
+	"github.com/stretchr/testify/require"
+)
+
+// batchStub - source of batch results, first transaction fails in batch if failFirst is set
+type batchStub struct {
+	mu        sync.Mutex
+	failFirst bool
+	calls     int
+}
+
+func (s *batchStub) GetBatchResult(_ context.Context, _ string, txID string) (*utils.BatchTxResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.failFirst && s.calls == 1 {
+		return &utils.BatchTxResult{TxID: txID, Error: &utils.BatchTxError{Code: 500, Error: "failed"}}, nil
+	}
+	return &utils.BatchTxResult{TxID: txID}, nil
+}
+
+func TestUserPool(t *testing.T) {
+	client, issuer := newFakeClient(t)
+	source := &batchStub{}
+
+	tests := []struct {
+		name   string
+		tokens []utils.PoolToken
+		source utils.BatchResultSource
+		err    bool
+	}{
+		{name: "not funded"},
+		{name: "funded in every token", tokens: []utils.PoolToken{
+			{Channel: utils.ChannelFiat, Issuer: issuer, Amount: "10"},
+			{Channel: utils.ChannelCC, Issuer: issuer, Amount: "5"},
+		}, source: source},
+		{name: "funded without source", tokens: []utils.PoolToken{{Channel: utils.ChannelFiat, Issuer: issuer, Amount: "10"}}, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, err := utils.NewUserPool(client, utils.DefaultNetwork(), utils.UserPoolConfig{Tokens: tt.tokens, Source: tt.source, Size: 2})
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer pool.Close()
+
+			taken := make(map[string]bool)
+			for i := 0; i < 3; i++ {
+				user, err := pool.TakeContext(context.Background())
+				require.NoError(t, err)
+				require.False(t, taken[user.UserAddressBase58Check], "user is handed out twice")
+				taken[user.UserAddressBase58Check] = true
+
+				for _, token := range tt.tokens {
+					balance, err := utils.QueryAmount(client, token.Channel, "balanceOf", user.UserAddressBase58Check)
+					require.NoError(t, err)
+					require.Equal(t, token.Amount, balance.String())
+				}
+			}
+			require.Len(t, pool.Taken(), 3)
+		})
+	}
+}
+
+func TestUserPoolSkipsUnfundedUsers(t *testing.T) {
+	client, issuer := newFakeClient(t)
+	recorder := fake.NewRecorder(client)
+	source := &batchStub{failFirst: true}
+
+	pool, err := utils.NewUserPool(recorder, utils.DefaultNetwork(), utils.UserPoolConfig{
+		Tokens: []utils.PoolToken{{Channel: utils.ChannelFiat, Issuer: issuer, Amount: "10"}},
+		Source: source,
+		Size:   2,
+	})
+	require.NoError(t, err)
+
+	user, err := pool.TakeContext(context.Background())
+	require.NoError(t, err)
+
+	var cleaned []string
+	err = pool.Cleanup(context.Background(), func(_ context.Context, user utils.User) error {
+		cleaned = append(cleaned, user.UserAddressBase58Check)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Error(t, pool.Err(), "failed emission is reported")
+
+	emits := recorder.CallsOf("emit")
+	require.NotEmpty(t, emits)
+	require.NotEqual(t, emits[0].Args[3], user.UserAddressBase58Check, "user of failed emission is not handed out")
+	require.Contains(t, cleaned, user.UserAddressBase58Check)
+	require.Contains(t, cleaned, emits[0].Args[3], "user of failed emission is cleaned up")
+}
@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+const (
+	// ReadinessTimeout - time to wait until stand is ready, example 5m, default DefaultReadinessTimeout
+	ReadinessTimeout = "READINESS_TIMEOUT"
+	// DefaultReadinessTimeout - time to wait until stand is ready if ReadinessTimeout is not set
+	DefaultReadinessTimeout = 2 * time.Minute
+	// ReadinessPollInterval - interval between attempts of failed probe
+	ReadinessPollInterval = 2 * time.Second
+
+	readinessEmitAmount = "1"
+)
+
+// ReadinessProbe struct for one check of stand readiness
+// Name - name of probe in errors and steps, example hlf proxy
+// Check - returns error while checked service is not ready
+type ReadinessProbe struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// ProxyProbe - probe querying method checkKeys of acl chaincode with public key of registered user or issuer
+func ProxyProbe(hlfProxy ChaincodeClient, network Network, publicKeyBase58 string) ReadinessProbe {
+	return ReadinessProbe{
+		Name: "hlf proxy",
+		Check: func(ctx context.Context) error {
+			_, err := hlfProxy.QueryContext(ctx, network.Channel(ChannelACL).Name, "checkKeys", publicKeyBase58)
+			return err
+		},
+	}
+}
+
+// ObserverProbe - probe requesting observer api url, observer is ready if it responds without server error
+func ObserverProbe(observerAPIURL string) ReadinessProbe {
+	return ReadinessProbe{
+		Name: "observer",
+		Check: func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, observerAPIURL, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return err
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode >= http.StatusInternalServerError {
+				return fmt.Errorf("observer status code %d", resp.StatusCode)
+			}
+			return nil
+		},
+	}
+}
+
+// BatchProbe - probe emitting one token of channel to userAddressBase58Check and waiting until emission is executed in batch by robot.
+// Token is emitted once, retries wait for result of the same emission and emit again only if emission failed.
+// Issuer address can be used as userAddressBase58Check to keep balances of test users untouched
+func BatchProbe(
+	hlfProxy ChaincodeClient,
+	network Network,
+	key string,
+	issuer Issuer,
+	userAddressBase58Check string,
+	source BatchResultSource,
+) ReadinessProbe {
+	var txID string
+	return ReadinessProbe{
+		Name: "batch",
+		Check: func(ctx context.Context) error {
+			channel := network.Channel(key)
+			if txID == "" {
+				res, err := EmitContext(ctx, hlfProxy, userAddressBase58Check, issuer, channel.Name, channel.Chaincode, readinessEmitAmount)
+				if err != nil {
+					return err
+				}
+				txID = res.TransactionID
+			}
+			result, err := WaitBatchResult(ctx, source, channel.Name, txID)
+			if err != nil {
+				return err
+			}
+			if result.Error != nil {
+				failed := txID
+				txID = ""
+				return fmt.Errorf("batch tx %s failed: %s", failed, result.Error.Error)
+			}
+			return nil
+		},
+	}
+}
+
+// StandProbes returns probes of hlf proxy, observer and batch of stand. Hlf proxy and batch are probed
+// with issuer of fiat channel, batch results are taken from observer. Returns error if issuer of fiat channel
+// or observer batch tx path is not set, compose probes with ObserverProbe and ProxyProbe for stands without them
+func StandProbes(stand Stand) ([]ReadinessProbe, error) {
+	issuer, err := stand.Issuer(ChannelFiat)
+	if err != nil {
+		return nil, fmt.Errorf("stand probes: %w", err)
+	}
+	address, err := GetAddressByPublicKey(issuer.IssuerEd25519PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("stand probes: %w", err)
+	}
+	source, err := stand.NewObserverBatchResultSource()
+	if err != nil {
+		return nil, fmt.Errorf("stand probes: %w", err)
+	}
+
+	hlfProxy := stand.NewHlfProxyService()
+	return []ReadinessProbe{
+		ProxyProbe(hlfProxy, stand.Network, issuer.IssuerEd25519PublicKeyBase58),
+		ObserverProbe(stand.ObserverAPIURL),
+		BatchProbe(hlfProxy, stand.Network, ChannelFiat, issuer, address, source),
+	}, nil
+}
+
+// WaitReady runs probes in order, retrying every failed probe until it succeeds or timeout expires
+func WaitReady(ctx context.Context, timeout time.Duration, probes ...ReadinessProbe) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for _, probe := range probes {
+		if err := waitProbe(ctx, probe); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckStandReady waits until every probe succeeds, timeout is taken from ReadinessTimeout env.
+// Intended to be called from BeforeAll of suite with StandProbes
+func CheckStandReady(t provider.T, probes ...ReadinessProbe) {
+	timeout := DefaultReadinessTimeout
+	if value := GetEnv(ReadinessTimeout, ""); value != "" {
+		var err error
+		timeout, err = time.ParseDuration(value)
+		t.Require().NoError(err, "invalid %s", ReadinessTimeout)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for _, probe := range probes {
+		probe := probe
+		t.WithNewStep("Waiting for "+probe.Name+" to be ready", func(sCtx provider.StepCtx) {
+			sCtx.Require().NoError(waitProbe(ctx, probe))
+		})
+	}
+}
+
+func waitProbe(ctx context.Context, probe ReadinessProbe) error {
+	for {
+		err := probe.Check(ctx)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s is not ready: %w, last error: %v", probe.Name, ctx.Err(), err)
+		case <-time.After(ReadinessPollInterval):
+		}
+	}
+}
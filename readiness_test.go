@@ -0,0 +1,139 @@
+package utils_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	utils "github.com/anoideaopen/testnet-util"
+	"github.com/anoideaopen/testnet-util/fake"
+	"github.com/stretchr/testify/require"
+)
+
+// resultsStub - source of batch results returning results in order, last result is repeated
+type resultsStub struct {
+	mu      sync.Mutex
+	results []error
+	txIDs   []string
+}
+
+func (s *resultsStub) GetBatchResult(_ context.Context, _ string, txID string) (*utils.BatchTxResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.txIDs = append(s.txIDs, txID)
+	err := s.results[0]
+	if len(s.results) > 1 {
+		s.results = s.results[1:]
+	}
+	var batchErr batchTxError
+	if errors.As(err, &batchErr) {
+		return &utils.BatchTxResult{TxID: txID, Error: &utils.BatchTxError{Code: 500, Error: batchErr.Error()}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &utils.BatchTxResult{TxID: txID}, nil
+}
+
+// batchTxError - error of transaction in batch returned by resultsStub as result
+type batchTxError string
+
+func (e batchTxError) Error() string {
+	return string(e)
+}
+
+func TestBatchProbe(t *testing.T) {
+	client, issuer := newFakeClient(t)
+	recorder := fake.NewRecorder(client)
+	network := utils.DefaultNetwork()
+
+	tests := []struct {
+		name    string
+		results []error
+		checks  int
+		emits   int
+	}{
+		{name: "ready", results: []error{nil}, checks: 1, emits: 1},
+		{name: "source not ready", results: []error{errors.New("observer is down"), nil}, checks: 2, emits: 1},
+		{name: "emission failed in batch", results: []error{batchTxError("failed"), nil}, checks: 2, emits: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder.Reset()
+			source := &resultsStub{results: tt.results}
+			probe := utils.BatchProbe(recorder, network, utils.ChannelFiat, issuer, "address", source)
+
+			var err error
+			for i := 0; i < tt.checks; i++ {
+				err = probe.Check(context.Background())
+			}
+			require.NoError(t, err)
+			require.Len(t, recorder.CallsOf("emit"), tt.emits)
+			require.Len(t, source.txIDs, tt.checks)
+		})
+	}
+}
+
+func TestProxyProbeContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	probe := utils.ProxyProbe(utils.NewHlfProxyService(server.URL, ""), utils.DefaultNetwork(), "key")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	require.ErrorIs(t, probe.Check(ctx), context.DeadlineExceeded)
+	require.Less(t, time.Since(started), time.Second)
+}
+
+func TestStandProbes(t *testing.T) {
+	_, issuer := newFakeClient(t)
+	privateKey, err := utils.ConvertPrivateKeyToBase58Check(issuer.IssuerEd25519PrivateKey)
+	require.NoError(t, err)
+	fiat := utils.DefaultNetwork().Channel(utils.ChannelFiat)
+	fiat.IssuerPrivateKey = privateKey
+
+	tests := []struct {
+		name    string
+		network utils.Network
+		txPath  string
+		probes  []string
+	}{
+		{name: "without fiat issuer", network: utils.DefaultNetwork(), txPath: "batch/transaction"},
+		{name: "without observer batch tx path", network: utils.DefaultNetwork().WithChannel(utils.ChannelFiat, fiat)},
+		{
+			name:    "with fiat issuer and observer batch tx path",
+			network: utils.DefaultNetwork().WithChannel(utils.ChannelFiat, fiat),
+			txPath:  "batch/transaction",
+			probes:  []string{"hlf proxy", "observer", "batch"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stand := utils.Stand{ObserverAPIURL: "http://localhost:3305", ObserverBatchTxPath: tt.txPath, Network: tt.network}
+			probes, err := utils.StandProbes(stand)
+			if tt.probes == nil {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			names := make([]string, 0, len(probes))
+			for _, probe := range probes {
+				names = append(names, probe.Name)
+			}
+			require.Equal(t, tt.probes, names)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+// ErrRedeemRequestNotFound - there is no redeem request with id in list of redeem requests
+var ErrRedeemRequestNotFound = errors.New("redeem request not found")
+
+// RedeemRequest struct for redeem request of fiat tokens
+// TransactionID - id of request, it is id of transaction request was created in
+type RedeemRequest struct {
+	TransactionID string   `json:"transactionId"`
+	UserAddress   string   `json:"userAddress"`
+	Amount        *big.Int `json:"amount"`
+	Ref           string   `json:"ref"`
+}
+
+// CreateRedeemRequest signs by user and invokes creation of request to redeem amount of tokens, tokens are taken from balance of user
+func CreateRedeemRequest(hlfProxy ChaincodeClient, user User, channel string, chaincode string, amount string, ref string) (*Response, error) {
+	return InvokeByUser(hlfProxy, user, channel, chaincode, "createRedeemRequest", amount, ref)
+}
+
+// AcceptRedeemRequest signs by issuer and invokes acceptance of redeem request, amount of tokens of request is burned
+func AcceptRedeemRequest(hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string, requestID string, amount string, ref string) (*Response, error) {
+	return invokeByIssuer(hlfProxy, issuer, channel, chaincode, "acceptRedeemRequest", requestID, amount, ref)
+}
+
+// DenyRedeemRequest signs by issuer and invokes denial of redeem request, amount of tokens of request is returned to user
+func DenyRedeemRequest(hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string, requestID string) (*Response, error) {
+	return invokeByIssuer(hlfProxy, issuer, channel, chaincode, "denyRedeemRequest", requestID)
+}
+
+// GetRedeemRequests returns redeem requests which are not accepted or denied yet
+func GetRedeemRequests(hlfProxy ChaincodeClient, channel string) ([]RedeemRequest, error) {
+	resp, err := hlfProxy.QueryContext(context.Background(), channel, "redeemRequestsList")
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []RedeemRequest
+	if err = json.Unmarshal(resp.Payload, &requests); err != nil {
+		return nil, fmt.Errorf("json unmarshal redeem requests: %w", err)
+	}
+	return requests, nil
+}
+
+// GetRedeemRequest returns redeem request by id, ErrRedeemRequestNotFound if it is accepted, denied or not created
+func GetRedeemRequest(hlfProxy ChaincodeClient, channel string, requestID string) (*RedeemRequest, error) {
+	requests, err := GetRedeemRequests(hlfProxy, channel)
+	if err != nil {
+		return nil, err
+	}
+	for i := range requests {
+		if requests[i].TransactionID == requestID {
+			return &requests[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrRedeemRequestNotFound, requestID)
+}
+
+// CreateRedeemRequestAndCheck creates request to redeem amount of tokens and checks that balance of user is decreased by amount
+// and request is in list of redeem requests, returns id of request
+func CreateRedeemRequestAndCheck(t provider.T, hlfProxy ChaincodeClient, user User, channel string, chaincode string, amount string) string {
+	var requestID string
+	address := user.UserAddressBase58Check
+	t.WithNewStep("Create redeem request of "+amount+" by user "+address, func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		value := parseAmount(sCtx, amount)
+		balance, err := QueryAmount(hlfProxy, channel, "balanceOf", address)
+		sCtx.Require().NoError(err)
+
+		res, err := CreateRedeemRequest(hlfProxy, user, channel, chaincode, amount, "ref redeem")
+		sCtx.Require().NoError(err)
+		time.Sleep(BatchTransactionTimeout)
+		requestID = res.TransactionID
+
+		checkBalanceChanged(sCtx, hlfProxy, channel, address, balance, new(big.Int).Neg(value))
+	})
+
+	t.WithNewStep("Checking that redeem request "+requestID+" is created", func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		request, err := GetRedeemRequest(hlfProxy, channel, requestID)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal(address, request.UserAddress)
+		sCtx.Require().Equal(amount, request.Amount.String())
+	})
+
+	return requestID
+}
+
+// AcceptRedeemRequestAndCheck accepts redeem request and checks that request is removed from list,
+// balance of user is not changed and total emission is decreased by burned amount of request
+func AcceptRedeemRequestAndCheck(t provider.T, hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string, requestID string) {
+	t.WithNewStep("Accept redeem request "+requestID, func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		request, err := GetRedeemRequest(hlfProxy, channel, requestID)
+		sCtx.Require().NoError(err)
+		balance, err := QueryAmount(hlfProxy, channel, "balanceOf", request.UserAddress)
+		sCtx.Require().NoError(err)
+		metadata, err := GetMetadata(hlfProxy, channel)
+		sCtx.Require().NoError(err)
+		sCtx.Require().NotNil(metadata.TotalEmission)
+
+		_, err = AcceptRedeemRequest(hlfProxy, issuer, channel, chaincode, requestID, request.Amount.String(), "ref accept redeem")
+		sCtx.Require().NoError(err)
+		time.Sleep(BatchTransactionTimeout)
+
+		checkRedeemRequestRemoved(sCtx, hlfProxy, channel, requestID)
+		checkBalanceChanged(sCtx, hlfProxy, channel, request.UserAddress, balance, new(big.Int))
+		expectedEmission := new(big.Int).Sub(metadata.TotalEmission, request.Amount)
+		metadata, err = GetMetadata(hlfProxy, channel)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal(expectedEmission.String(), metadata.TotalEmission.String(), "total emission")
+	})
+}
+
+// DenyRedeemRequestAndCheck denies redeem request and checks that request is removed from list
+// and amount of request is returned to balance of user
+func DenyRedeemRequestAndCheck(t provider.T, hlfProxy ChaincodeClient, issuer Issuer, channel string, chaincode string, requestID string) {
+	t.WithNewStep("Deny redeem request "+requestID, func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		request, err := GetRedeemRequest(hlfProxy, channel, requestID)
+		sCtx.Require().NoError(err)
+		balance, err := QueryAmount(hlfProxy, channel, "balanceOf", request.UserAddress)
+		sCtx.Require().NoError(err)
+
+		_, err = DenyRedeemRequest(hlfProxy, issuer, channel, chaincode, requestID)
+		sCtx.Require().NoError(err)
+		time.Sleep(BatchTransactionTimeout)
+
+		checkRedeemRequestRemoved(sCtx, hlfProxy, channel, requestID)
+		checkBalanceChanged(sCtx, hlfProxy, channel, request.UserAddress, balance, request.Amount)
+	})
+}
+
+func checkRedeemRequestRemoved(sCtx provider.StepCtx, hlfProxy ChaincodeClient, channel string, requestID string) {
+	_, err := GetRedeemRequest(hlfProxy, channel, requestID)
+	sCtx.Require().ErrorIs(err, ErrRedeemRequestNotFound)
+}
+
+// checkBalanceChanged checks that balance of address is equal to before plus delta
+func checkBalanceChanged(sCtx provider.StepCtx, hlfProxy ChaincodeClient, channel string, address string, before *big.Int, delta *big.Int) {
+	balance, err := QueryAmount(hlfProxy, channel, "balanceOf", address)
+	sCtx.Require().NoError(err)
+	sCtx.Require().Equal(new(big.Int).Add(before, delta).String(), balance.String(), "balance of %s", address)
+}
@@ -0,0 +1,121 @@
+package utils_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	utils "github.com/anoideaopen/testnet-util"
+	"github.com/anoideaopen/testnet-util/fake"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRedeemRequest(t *testing.T) {
+	client := fake.NewClient().
+		Handle("fiat", "redeemRequestsList", func(context.Context, []string) ([]byte, error) {
+			return []byte(`[{"transactionId":"tx1","userAddress":"alice","amount":10,"ref":"ref redeem"},` +
+				`{"transactionId":"tx2","userAddress":"bob","amount":20,"ref":"ref redeem"}]`), nil
+		}).
+		Handle("invalid", "redeemRequestsList", func(context.Context, []string) ([]byte, error) {
+			return []byte(`{}`), nil
+		})
+
+	tests := []struct {
+		name      string
+		channel   string
+		requestID string
+		user      string
+		amount    string
+		err       error
+		invalid   bool
+	}{
+		{name: "first", channel: "fiat", requestID: "tx1", user: "alice", amount: "10"},
+		{name: "last", channel: "fiat", requestID: "tx2", user: "bob", amount: "20"},
+		{name: "not found", channel: "fiat", requestID: "tx3", err: utils.ErrRedeemRequestNotFound},
+		{name: "invalid list", channel: "invalid", requestID: "tx1", invalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := utils.GetRedeemRequest(client, tt.channel, tt.requestID)
+			switch {
+			case tt.invalid:
+				var jsonErr *json.UnmarshalTypeError
+				require.ErrorAs(t, err, &jsonErr)
+			case tt.err != nil:
+				require.ErrorIs(t, err, tt.err)
+				require.ErrorContains(t, err, tt.requestID)
+			default:
+				require.NoError(t, err)
+				require.Equal(t, tt.requestID, request.TransactionID)
+				require.Equal(t, tt.user, request.UserAddress)
+				require.Equal(t, tt.amount, request.Amount.String())
+			}
+		})
+	}
+}
+
+func TestRedeemRequestHelpers(t *testing.T) {
+	client, issuer := newFakeClient(t)
+	ok := func(context.Context, []string) ([]byte, error) { return nil, nil }
+	client.
+		Handle("fiat", "createRedeemRequest", ok).
+		Handle("fiat", "acceptRedeemRequest", ok).
+		Handle("fiat", "denyRedeemRequest", ok)
+	recorder := fake.NewRecorder(client)
+
+	privateKey, _, err := utils.DerivePrivateAndPublicKey([]byte("redeem"), "user")
+	require.NoError(t, err)
+	user, err := utils.NewUser(privateKey)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		invoke func() (*utils.Response, error)
+		fcn    string
+		signer string
+		args   []string
+	}{
+		{
+			name: "create",
+			invoke: func() (*utils.Response, error) {
+				return utils.CreateRedeemRequest(recorder, user, "fiat", "fiat", "10", "ref redeem")
+			},
+			fcn:    "createRedeemRequest",
+			signer: user.UserPublicKeyBase58,
+			args:   []string{"10", "ref redeem"},
+		},
+		{
+			name: "accept",
+			invoke: func() (*utils.Response, error) {
+				return utils.AcceptRedeemRequest(recorder, issuer, "fiat", "fiat", "tx1", "10", "ref accept redeem")
+			},
+			fcn:    "acceptRedeemRequest",
+			signer: issuer.IssuerEd25519PublicKeyBase58,
+			args:   []string{"tx1", "10", "ref accept redeem"},
+		},
+		{
+			name: "deny",
+			invoke: func() (*utils.Response, error) {
+				return utils.DenyRedeemRequest(recorder, issuer, "fiat", "fiat", "tx1")
+			},
+			fcn:    "denyRedeemRequest",
+			signer: issuer.IssuerEd25519PublicKeyBase58,
+			args:   []string{"tx1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.invoke()
+			require.NoError(t, err)
+			require.NotEmpty(t, resp.TransactionID)
+
+			calls := recorder.CallsOf(tt.fcn)
+			require.Len(t, calls, 1)
+			args := calls[0].Args
+			// signed args: request id, chaincode, channel, args, nonce, public key, signature
+			require.Equal(t, []string{"fiat", "fiat"}, args[1:3])
+			require.Equal(t, tt.args, args[3:len(args)-3])
+			require.Equal(t, tt.signer, args[len(args)-2])
+		})
+	}
+}
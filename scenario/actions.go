@@ -0,0 +1,160 @@
+package scenario
+
+import (
+	"context"
+	"time"
+
+	utils "github.com/anoideaopen/testnet-util"
+	"github.com/ozontech/allure-go/pkg/allure"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"golang.org/x/crypto/ed25519"
+)
+
+// action - executes step with expanded params in allure step
+type action func(sCtx provider.StepCtx, r *runner, step Step)
+
+var actions = map[string]action{
+	"addUser":             addUser,
+	"emit":                emit,
+	"transfer":            transfer,
+	"swap":                swap,
+	"checkBalance":        checkBalance,
+	"checkAllowedBalance": checkAllowedBalance,
+	"invoke":              invoke,
+	"query":               query,
+	"sleep":               sleep,
+}
+
+// addUser adds user in acl, keys are derived from params seed and name if seed is set.
+// User is saved by alias As, variables As.address and As.publicKey are captured
+func addUser(sCtx provider.StepCtx, r *runner, step Step) {
+	var (
+		privateKey ed25519.PrivateKey
+		err        error
+	)
+	if seed := step.Params["seed"]; seed != "" {
+		privateKey, _, err = utils.DerivePrivateAndPublicKey([]byte(seed), step.Params["name"])
+	} else {
+		privateKey, _, err = utils.GeneratePrivateAndPublicKey()
+	}
+	sCtx.Require().NoError(err)
+
+	user, err := utils.NewUser(privateKey)
+	sCtx.Require().NoError(err)
+
+	_, err = utils.RegisterUser(r.hlfProxy, r.network, user)
+	if err != nil {
+		sCtx.Require().Contains(err.Error(), "already exists")
+	}
+	r.waitBatch()
+
+	_, err = r.hlfProxy.QueryContext(context.Background(), r.network.Channel(utils.ChannelACL).Name, "checkKeys", user.UserPublicKeyBase58)
+	sCtx.Require().NoError(err)
+
+	r.users[step.As] = user
+	r.capture(step.As+".address", user.UserAddressBase58Check)
+	r.capture(step.As+".publicKey", user.UserPublicKeyBase58)
+	sCtx.WithNewParameters("address", user.UserAddressBase58Check)
+}
+
+// emit emits params amount to params to in channel params channel by issuer of channel or params issuerKey
+func emit(sCtx provider.StepCtx, r *runner, step Step) {
+	channelKey := param(step, "channel", utils.ChannelFiat)
+
+	var (
+		issuer utils.Issuer
+		err    error
+	)
+	if key := step.Params["issuerKey"]; key != "" {
+		issuer, err = utils.NewIssuer(key)
+	} else {
+		issuer, err = r.network.Issuer(channelKey)
+	}
+	sCtx.Require().NoError(err)
+
+	ch := r.network.Channel(channelKey)
+	resp, err := utils.Emit(r.hlfProxy, step.Params["to"], issuer, ch.Name, ch.Chaincode, step.Params["amount"])
+	sCtx.Require().NoError(err)
+	r.committed(step, resp)
+}
+
+// transfer transfers params amount from user with alias params from to address params to
+func transfer(sCtx provider.StepCtx, r *runner, step Step) {
+	from, err := r.user(step.Params["from"])
+	sCtx.Require().NoError(err)
+
+	ch := r.network.Channel(param(step, "channel", utils.ChannelFiat))
+	resp, err := utils.Transfer(r.hlfProxy, from, step.Params["to"], ch.Name, ch.Chaincode, step.Params["amount"], param(step, "ref", "ref transfer"))
+	sCtx.Require().NoError(err)
+	r.committed(step, resp)
+}
+
+// swap swaps params amount of user params user from channel params from to channel params to
+func swap(sCtx provider.StepCtx, r *runner, step Step) {
+	user, err := r.user(step.Params["user"])
+	sCtx.Require().NoError(err)
+	chFrom := r.network.Channel(param(step, "from", utils.ChannelFiat))
+	chTo := r.network.Channel(param(step, "to", utils.ChannelCC))
+
+	begin, err := utils.SwapBegin(r.hlfProxy, user, chFrom, chTo, step.Params["amount"])
+	sCtx.Require().NoError(err)
+	r.waitBatch()
+
+	_, err = utils.SwapDone(r.hlfProxy, chTo, begin.TransactionID)
+	sCtx.Require().NoError(err)
+	r.committed(step, begin)
+}
+
+// checkBalance checks that balance of params address in channel params channel is equal to params amount
+func checkBalance(sCtx provider.StepCtx, r *runner, step Step) {
+	channel := r.network.Channel(param(step, "channel", utils.ChannelFiat)).Name
+	utils.CheckBalanceEqualInStep(sCtx, r.hlfProxy, step.Params["address"], channel, step.Params["amount"])
+}
+
+// checkAllowedBalance checks that allowed balance of params token of params address is equal to params amount
+func checkAllowedBalance(sCtx provider.StepCtx, r *runner, step Step) {
+	channel := r.network.Channel(param(step, "channel", utils.ChannelCC)).Name
+	utils.CheckAllowedBalanceEqualInStep(sCtx, r.hlfProxy, step.Params["address"], channel, step.Params["token"], step.Params["amount"])
+}
+
+// invoke invokes params fcn with args in channel params channel, args are signed by user params signer if set
+func invoke(sCtx provider.StepCtx, r *runner, step Step) {
+	ch := r.network.Channel(step.Params["channel"])
+
+	var (
+		resp *utils.Response
+		err  error
+	)
+	if signer := step.Params["signer"]; signer != "" {
+		user, uErr := r.user(signer)
+		sCtx.Require().NoError(uErr)
+		resp, err = utils.InvokeByUser(r.hlfProxy, user, ch.Name, ch.Chaincode, step.Params["fcn"], step.Args...)
+	} else {
+		resp, err = r.hlfProxy.InvokeContext(context.Background(), ch.Name, step.Params["fcn"], step.Args...)
+	}
+	sCtx.Require().NoError(err)
+	r.committed(step, resp)
+}
+
+// query queries params fcn with args in channel params channel, payload is captured
+func query(sCtx provider.StepCtx, r *runner, step Step) {
+	resp, err := r.hlfProxy.QueryContext(context.Background(), r.network.Channel(step.Params["channel"]).Name, step.Params["fcn"], step.Args...)
+	sCtx.Require().NoError(err)
+	sCtx.WithNewAttachment("payload", allure.Text, resp.Payload)
+	r.capture(step.As, string(resp.Payload))
+}
+
+// sleep waits params duration, example 2s, batch timeout of scenario by default
+func sleep(sCtx provider.StepCtx, r *runner, step Step) {
+	d, err := time.ParseDuration(param(step, "duration", r.batchTimeout.String()))
+	sCtx.Require().NoError(err)
+	time.Sleep(d)
+}
+
+// param returns step param by name or defaultValue if it is not set
+func param(step Step, name string, defaultValue string) string {
+	if v, ok := step.Params[name]; ok && v != "" {
+		return v
+	}
+	return defaultValue
+}
@@ -0,0 +1,186 @@
+package scenario
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	utils "github.com/anoideaopen/testnet-util"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario struct for sequence of steps read from yaml file
+// Vars - initial variables, used in step params as ${name}
+// BatchTimeout - time to wait for batch after invokes, example 500ms, utils.BatchTransactionTimeout if empty
+type Scenario struct {
+	Name         string            `yaml:"name"`
+	Vars         map[string]string `yaml:"vars"`
+	BatchTimeout string            `yaml:"batchTimeout"`
+	Steps        []Step            `yaml:"steps"`
+}
+
+// Step struct for one step of scenario
+// Action - one of actions, example addUser, emit, transfer, swap, checkBalance, invoke, query
+// Name - title of allure step, action is used if empty
+// As - variable name output of step is captured to: user alias for addUser, tx id for invokes, payload for query
+// Args - arguments of raw invoke and query
+// Params - other params of action, example channel, to, amount
+type Step struct {
+	Action string            `yaml:"action"`
+	Name   string            `yaml:"name"`
+	As     string            `yaml:"as"`
+	Args   []string          `yaml:"args"`
+	Params map[string]string `yaml:",inline"`
+}
+
+var varPattern = regexp.MustCompile(`\$\{([A-Za-z0-9_.\-]+)}`)
+
+// Load reads scenario from yaml file
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read scenario: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse reads scenario from yaml
+func Parse(data []byte) (*Scenario, error) {
+	s := &Scenario{}
+	if err := yaml.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("yaml unmarshal scenario: %w", err)
+	}
+	if _, err := s.batchTimeout(); err != nil {
+		return nil, err
+	}
+	for i, step := range s.Steps {
+		if _, ok := actions[step.Action]; !ok {
+			return nil, fmt.Errorf("step %d: unknown action %s", i+1, step.Action)
+		}
+		if step.Action == "addUser" && step.As == "" {
+			return nil, fmt.Errorf("step %d: action addUser needs alias in as", i+1)
+		}
+	}
+	return s, nil
+}
+
+// batchTimeout returns BatchTimeout as duration
+func (s *Scenario) batchTimeout() (time.Duration, error) {
+	if s.BatchTimeout == "" {
+		return utils.BatchTransactionTimeout, nil
+	}
+	d, err := time.ParseDuration(s.BatchTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("batch timeout: %w", err)
+	}
+	return d, nil
+}
+
+// RunFile reads scenario from yaml file and runs it
+func RunFile(t provider.T, path string, hlfProxy utils.ChaincodeClient, network utils.Network) {
+	var s *Scenario
+	t.WithNewStep("Load scenario "+path, func(sCtx provider.StepCtx) {
+		var err error
+		s, err = Load(path)
+		sCtx.Require().NoError(err)
+	})
+	s.Run(t, hlfProxy, network)
+}
+
+// Run executes steps of scenario one by one, each step is reported as allure step
+func (s *Scenario) Run(t provider.T, hlfProxy utils.ChaincodeClient, network utils.Network) {
+	batchTimeout, err := s.batchTimeout()
+	t.Require().NoError(err)
+	r := &runner{
+		hlfProxy:     hlfProxy,
+		network:      network,
+		batchTimeout: batchTimeout,
+		vars:         make(map[string]string, len(s.Vars)),
+		users:        make(map[string]utils.User),
+	}
+	for k, v := range s.Vars {
+		r.vars[k] = v
+	}
+
+	for i, step := range s.Steps {
+		title := step.Name
+		if title == "" {
+			title = step.Action
+		}
+		t.WithNewStep("Step "+strconv.Itoa(i+1)+": "+title, func(sCtx provider.StepCtx) {
+			params, err := r.expand(step)
+			sCtx.Require().NoError(err)
+			for k, v := range params.Params {
+				sCtx.WithNewParameters(k, v)
+			}
+			// calls of step are attached to its allure step, vars and users are shared by steps
+			sr := *r
+			sr.hlfProxy = utils.ClientInStep(r.hlfProxy, sCtx)
+			actions[step.Action](sCtx, &sr, params)
+		})
+	}
+}
+
+// runner - state of scenario run: variables and users added by steps
+type runner struct {
+	hlfProxy     utils.ChaincodeClient
+	network      utils.Network
+	batchTimeout time.Duration
+	vars         map[string]string
+	users        map[string]utils.User
+}
+
+// expand returns copy of step with variables in params and args replaced by values
+func (r *runner) expand(step Step) (Step, error) {
+	var err error
+	replace := func(s string) string {
+		return varPattern.ReplaceAllStringFunc(s, func(m string) string {
+			name := varPattern.FindStringSubmatch(m)[1]
+			v, ok := r.vars[name]
+			if !ok && err == nil {
+				err = fmt.Errorf("variable %s is not defined", name)
+			}
+			return v
+		})
+	}
+
+	res := Step{Action: step.Action, Name: step.Name, As: step.As, Params: make(map[string]string, len(step.Params))}
+	for k, v := range step.Params {
+		res.Params[k] = replace(v)
+	}
+	for _, a := range step.Args {
+		res.Args = append(res.Args, replace(a))
+	}
+
+	return res, err
+}
+
+// user returns user added by step with As equal to alias
+func (r *runner) user(alias string) (utils.User, error) {
+	user, ok := r.users[alias]
+	if !ok {
+		return utils.User{}, fmt.Errorf("user %s is not added", alias)
+	}
+	return user, nil
+}
+
+// waitBatch waits until invoke is executed in batch
+func (r *runner) waitBatch() {
+	time.Sleep(r.batchTimeout)
+}
+
+// committed waits for batch of invoke and captures its tx id
+func (r *runner) committed(step Step, resp *utils.Response) {
+	r.waitBatch()
+	r.capture(step.As, resp.TransactionID)
+}
+
+// capture saves value to variable as if it is not empty
+func (r *runner) capture(as string, value string) {
+	if as != "" {
+		r.vars[as] = value
+	}
+}
@@ -0,0 +1,161 @@
+package scenario
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	utils "github.com/anoideaopen/testnet-util"
+	"github.com/anoideaopen/testnet-util/fake"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	allure "github.com/ozontech/allure-go/pkg/framework/runner"
+	"github.com/stretchr/testify/require"
+)
+
+const testScenario = "testdata/transfer.yaml"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		yaml  string
+		steps int
+		err   string
+	}{
+		{
+			name:  "valid",
+			yaml:  "steps:\n  - action: addUser\n    as: alice\n  - action: sleep\n    duration: 1s\n",
+			steps: 2,
+		},
+		{name: "unknown action", yaml: "steps:\n  - action: burn\n", err: "step 1: unknown action burn"},
+		{name: "add user without alias", yaml: "steps:\n  - action: addUser\n", err: "step 1: action addUser needs alias in as"},
+		{name: "invalid batch timeout", yaml: "batchTimeout: soon\nsteps: []\n", err: "batch timeout"},
+		{name: "invalid yaml", yaml: "steps: {", err: "yaml unmarshal scenario"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse([]byte(tt.yaml))
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, s.Steps, tt.steps)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	s, err := Load(testScenario)
+	require.NoError(t, err)
+	require.Equal(t, "transfer and swap", s.Name)
+	require.Equal(t, "10", s.Vars["amount"])
+	require.Len(t, s.Steps, 8)
+	require.Equal(t, Step{
+		Action: "query",
+		As:     "swap",
+		Args:   []string{"${swapTx}"},
+		Params: map[string]string{"channel": "fiat", "fcn": "swapGet"},
+	}, s.Steps[7])
+
+	_, err = Load("testdata/missing.yaml")
+	require.ErrorContains(t, err, "read scenario")
+}
+
+func TestExpand(t *testing.T) {
+	r := &runner{vars: map[string]string{"amount": "10", "alice.address": "addr"}}
+
+	tests := []struct {
+		name string
+		step Step
+		want Step
+		err  string
+	}{
+		{
+			name: "params and args",
+			step: Step{Action: "invoke", As: "tx", Args: []string{"${alice.address}", "${amount}0"}, Params: map[string]string{"to": "${alice.address}"}},
+			want: Step{Action: "invoke", As: "tx", Args: []string{"addr", "100"}, Params: map[string]string{"to": "addr"}},
+		},
+		{
+			name: "without variables",
+			step: Step{Action: "sleep", Params: map[string]string{"duration": "1s"}},
+			want: Step{Action: "sleep", Params: map[string]string{"duration": "1s"}},
+		},
+		{
+			name: "unknown variable",
+			step: Step{Action: "emit", Params: map[string]string{"to": "${bob.address}"}},
+			err:  "variable bob.address is not defined",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, err := r.expand(tt.step)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, step)
+		})
+	}
+}
+
+func TestCapture(t *testing.T) {
+	r := &runner{vars: map[string]string{}}
+	r.capture("tx", "id")
+	r.capture("", "ignored")
+	require.Equal(t, map[string]string{"tx": "id"}, r.vars)
+}
+
+func TestRun(t *testing.T) {
+	t.Setenv("ALLURE_OUTPUT_PATH", t.TempDir())
+
+	client := fake.NewClient()
+	fake.NewACL().Register(client, "acl")
+	fiat := fake.NewToken()
+	fiat.Register(client, "fiat")
+	cc := fake.NewToken()
+	cc.Register(client, "cc")
+	// swapBegin of fake keeps swap, swapDone moves its amount to allowed balance of signer in cc
+	var pending struct{ address, token, amount string }
+	client.
+		Handle("fiat", "swapBegin", func(_ context.Context, args []string) ([]byte, error) {
+			address, err := utils.GetAddressByPublicKeyBase58(args[len(args)-2])
+			pending.address, pending.token, pending.amount = address, args[3], args[5]
+			return nil, err
+		}).
+		Handle("cc", "swapDone", func(context.Context, []string) ([]byte, error) {
+			amount, _ := new(big.Int).SetString(pending.amount, 10)
+			cc.SetAllowedBalance(pending.address, pending.token, amount)
+			return nil, nil
+		}).
+		Handle("fiat", "swapGet", func(_ context.Context, args []string) ([]byte, error) {
+			return []byte(args[0]), nil
+		})
+	recorder := fake.NewRecorder(client)
+
+	privateKey, _, err := utils.DerivePrivateAndPublicKey([]byte("scenario"), "issuer")
+	require.NoError(t, err)
+	issuerKey, err := utils.ConvertPrivateKeyToBase58Check(privateKey)
+	require.NoError(t, err)
+
+	s, err := Load(testScenario)
+	require.NoError(t, err)
+	s.Vars["issuerKey"] = issuerKey
+	s.BatchTimeout = "0s"
+
+	allure.Run(t, "scenario", func(pt provider.T) {
+		s.Run(pt, recorder, utils.DefaultNetwork())
+	})
+	require.False(t, t.Failed())
+
+	begin := recorder.CallsOf("swapBegin")
+	require.Len(t, begin, 1)
+	done := recorder.CallsOf("swapDone")
+	require.Len(t, done, 1)
+	require.Equal(t, begin[0].Response.TransactionID, done[0].Args[0], "swapDone finishes swap of swapBegin")
+	get := recorder.CallsOf("swapGet")
+	require.Len(t, get, 1)
+	require.Equal(t, []string{begin[0].Response.TransactionID}, get[0].Args, "tx id of swap is captured")
+	require.Len(t, recorder.CallsOf("addUser"), 2)
+	require.Len(t, recorder.CallsOf("transfer"), 1)
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/sha3"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// MasterSeed - master seed for deterministic test users, any string
+	MasterSeed = "MASTER_SEED"
+	// MasterMnemonic - BIP-39 mnemonic for deterministic test users, used if MasterSeed is not set
+	MasterMnemonic = "MASTER_MNEMONIC"
+
+	mnemonicIterations = 2048
+	mnemonicSeedLen    = 64
+)
+
+// ErrInvalidMnemonic - mnemonic has words out of BIP-39 english word list, wrong number of words or wrong checksum
+var ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+// SeedFromMnemonic - get BIP-39 seed by mnemonic and passphrase. Mnemonic is validated against english word list
+// and checksum, mnemonic and passphrase are NFKD normalized, so seed is the same as in BIP-39 wallets
+func SeedFromMnemonic(mnemonic string, passphrase string) ([]byte, error) {
+	normalized := norm.NFKD.String(strings.Join(strings.Fields(mnemonic), " "))
+	if _, err := bip39.EntropyFromMnemonic(normalized); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMnemonic, err)
+	}
+	salt := norm.NFKD.String("mnemonic" + passphrase)
+	return pbkdf2.Key([]byte(normalized), []byte(salt), mnemonicIterations, mnemonicSeedLen, sha512.New), nil
+}
+
+// GetMasterSeed - get master seed from MasterSeed or MasterMnemonic env
+func GetMasterSeed() ([]byte, error) {
+	if seed := GetEnv(MasterSeed, ""); seed != "" {
+		return []byte(seed), nil
+	}
+	if mnemonic := GetEnv(MasterMnemonic, ""); mnemonic != "" {
+		return SeedFromMnemonic(mnemonic, "")
+	}
+	return nil, errors.New("neither " + MasterSeed + " nor " + MasterMnemonic + " is set")
+}
+
+// DerivePrivateAndPublicKey - create private and public key deterministically by master seed and name,
+// same seed and name always give same keys and address. Key seed is sha3-256 of seed, zero byte and name,
+// derivation is not BIP-32/SLIP-10, so keys do not match keys of wallets for the same mnemonic
+func DerivePrivateAndPublicKey(seed []byte, name string) (ed25519.PrivateKey, ed25519.PublicKey, error) {
+	if len(seed) == 0 {
+		return nil, nil, errors.New("seed can't be empty")
+	}
+
+	msg := append(append(append([]byte{}, seed...), 0), name...)
+	keySeed := sha3.Sum256(msg)
+	privateKey := ed25519.NewKeyFromSeed(keySeed[:])
+	publicKey, ok := privateKey.Public().(ed25519.PublicKey)
+	if !ok {
+		return nil, nil, errors.New("type assertion failed")
+	}
+	return privateKey, publicKey, nil
+}
+
+// DerivePrivateAndPublicKeyByIndex - create private and public key deterministically by master seed and index
+func DerivePrivateAndPublicKeyByIndex(seed []byte, index int) (ed25519.PrivateKey, ed25519.PublicKey, error) {
+	return DerivePrivateAndPublicKey(seed, strconv.Itoa(index))
+}
+
+// AddUserFromSeed adds user with keys derived from seed and name in acl channel of stand selected by env, user may already exist
+func AddUserFromSeed(t provider.T, hlfProxy ChaincodeClient, seed []byte, name string) User {
+	return AddUserFromSeedToNetwork(t, hlfProxy, standNetwork(t), seed, name)
+}
+
+// AddUserFromSeedToNetwork adds user with keys derived from seed and name in acl channel of network, user may already exist
+func AddUserFromSeedToNetwork(t provider.T, hlfProxy ChaincodeClient, network Network, seed []byte, name string) User {
+	var privateKey ed25519.PrivateKey
+
+	t.WithNewStep("Derive cryptos for user "+name+" from seed", func(sCtx provider.StepCtx) {
+		var err error
+		privateKey, _, err = DerivePrivateAndPublicKey(seed, name)
+		sCtx.Require().NoError(err)
+	})
+
+	return AddUserWithPrivateKeyToNetwork(t, hlfProxy, network, privateKey)
+}
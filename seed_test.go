@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeedFromMnemonic(t *testing.T) {
+	tests := []struct {
+		name       string
+		mnemonic   string
+		passphrase string
+		seed       string
+		err        error
+	}{
+		{
+			name:       "bip-39 test vector",
+			mnemonic:   "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			passphrase: "TREZOR",
+			seed:       "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			name:       "extra spaces are ignored",
+			mnemonic:   "  abandon abandon abandon abandon abandon abandon\tabandon abandon abandon abandon abandon   about ",
+			passphrase: "TREZOR",
+			seed:       "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			name:     "wrong checksum",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+			err:      ErrInvalidMnemonic,
+		},
+		{
+			name:     "unknown word",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon testnet",
+			err:      ErrInvalidMnemonic,
+		},
+		{
+			name:     "wrong number of words",
+			mnemonic: "abandon abandon about",
+			err:      ErrInvalidMnemonic,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seed, err := SeedFromMnemonic(tt.mnemonic, tt.passphrase)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.seed, hex.EncodeToString(seed))
+		})
+	}
+}
+
+func TestDerivePrivateAndPublicKey(t *testing.T) {
+	seed := []byte("master seed")
+	privateKey, publicKey, err := DerivePrivateAndPublicKey(seed, "alice")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		seed  []byte
+		user  string
+		equal bool
+		err   bool
+	}{
+		{name: "same seed and name", seed: seed, user: "alice", equal: true},
+		{name: "other name", seed: seed, user: "bob"},
+		{name: "other seed", seed: []byte("other seed"), user: "alice"},
+		{name: "name is not concatenated with seed", seed: []byte("master seedalice"), user: ""},
+		{name: "empty seed", seed: nil, user: "alice", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			private, public, err := DerivePrivateAndPublicKey(tt.seed, tt.user)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.equal, privateKey.Equal(private))
+			require.Equal(t, tt.equal, publicKey.Equal(public))
+		})
+	}
+
+	private, _, err := DerivePrivateAndPublicKeyByIndex(seed, 7)
+	require.NoError(t, err)
+	byName, _, err := DerivePrivateAndPublicKey(seed, "7")
+	require.NoError(t, err)
+	require.True(t, byName.Equal(private))
+}
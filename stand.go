@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+const (
+	// StandProfile - name of stand profile to run tests against, example dev, stage or local
+	StandProfile = "STAND_PROFILE"
+	// StandProfilesPath - path to json file with stand profiles, see LoadStands
+	StandProfilesPath = "STAND_PROFILES_PATH"
+	// DefaultStandProfile - name of built-in profile used when StandProfile is not set
+	DefaultStandProfile = "local"
+)
+
+// Stand struct describes one stand: addresses of services, issuer key and network of channels
+type Stand struct {
+	Name                 string   `json:"-"`
+	HlfProxyURL          string   `json:"hlfProxyUrl"`
+	HlfProxyAuthToken    string   `json:"hlfProxyAuthToken"`
+	FiatIssuerPrivateKey string   `json:"fiatIssuerPrivateKey"`
+	ObserverAPIURL       string   `json:"observerApiUrl"`
+	ObserverBatchTxPath  string   `json:"observerBatchTxPath"`
+	CorrectNodeName      string   `json:"correctNodeName"`
+	Endpoints            []string `json:"endpoints"`
+	Network
+}
+
+// LocalStand returns built-in profile for stand started locally in docker
+func LocalStand() Stand {
+	return Stand{
+		Name:           DefaultStandProfile,
+		HlfProxyURL:    "http://localhost:9001",
+		ObserverAPIURL: "http://localhost:3305",
+		Network:        DefaultNetwork(),
+	}
+}
+
+// LoadStands reads stand profiles from json file where key is profile name, example
+// {"dev": {"hlfProxyUrl": "http://dev:9001", "channels": {"fiat": {"name": "fiat", "chaincode": "fiat", "ticker": "FIAT"}}}}
+func LoadStands(path string) (map[string]Stand, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read stand profiles: %w", err)
+	}
+
+	stands := make(map[string]Stand)
+	if err = json.Unmarshal(data, &stands); err != nil {
+		return nil, fmt.Errorf("json unmarshal stand profiles: %w", err)
+	}
+
+	for name, stand := range stands {
+		stand.Name = name
+		stands[name] = stand
+	}
+
+	return stands, nil
+}
+
+// GetStand returns stand profile selected by StandProfile env.
+// Profiles are read from file in StandProfilesPath env in addition to built-in LocalStand.
+// Env HlfProxyURL, HlfProxyAuthToken, FiatIssuerPrivateKey, ObserverAPIURL, ObserverBatchTxPath, CorrectNodeName and PeerEndpoints
+// override values from profile if set
+func GetStand() (Stand, error) {
+	stands := map[string]Stand{DefaultStandProfile: LocalStand()}
+
+	if path := GetEnv(StandProfilesPath, ""); path != "" {
+		loaded, err := LoadStands(path)
+		if err != nil {
+			return Stand{}, err
+		}
+		for name, stand := range loaded {
+			stands[name] = stand
+		}
+	}
+
+	name := GetEnv(StandProfile, DefaultStandProfile)
+	stand, ok := stands[name]
+	if !ok {
+		return Stand{}, fmt.Errorf("stand profile %s not found", name)
+	}
+
+	stand.HlfProxyURL = GetEnv(HlfProxyURL, stand.HlfProxyURL)
+	stand.HlfProxyAuthToken = GetEnv(HlfProxyAuthToken, stand.HlfProxyAuthToken)
+	stand.FiatIssuerPrivateKey = GetEnv(FiatIssuerPrivateKey, stand.FiatIssuerPrivateKey)
+	stand.ObserverAPIURL = GetEnv(ObserverAPIURL, stand.ObserverAPIURL)
+	stand.ObserverBatchTxPath = GetEnv(ObserverBatchTxPath, stand.ObserverBatchTxPath)
+	stand.CorrectNodeName = GetEnv(CorrectNodeName, stand.CorrectNodeName)
+	if endpoints := GetEnv(PeerEndpoints, ""); endpoints != "" {
+		stand.Endpoints = strings.Split(endpoints, ",")
+	}
+
+	fiat := stand.Channel(ChannelFiat)
+	if fiat.IssuerPrivateKey == "" && stand.FiatIssuerPrivateKey != "" {
+		fiat.IssuerPrivateKey = stand.FiatIssuerPrivateKey
+		stand.Network = stand.Network.WithChannel(ChannelFiat, fiat)
+	}
+
+	return stand, nil
+}
+
+// NewHlfProxyService - create new instance of HlfProxyService for stand
+func (s Stand) NewHlfProxyService(opts ...HlfProxyOption) *HlfProxyService {
+	return NewHlfProxyService(s.HlfProxyURL, s.HlfProxyAuthToken, opts...)
+}
+
+// WithCorrectNode - send requests of HlfProxyService to CorrectNodeName peer of stand only,
+// example stand.NewHlfProxyService(stand.WithCorrectNode()). Option does nothing if CorrectNodeName is not set
+// and peers are chosen by hlf proxy
+func (s Stand) WithCorrectNode() HlfProxyOption {
+	if s.CorrectNodeName == "" {
+		return func(*HlfProxyService) {}
+	}
+	return WithTargetEndpoints(s.CorrectNodeName)
+}
+
+// NewHTTPClient - create new instance of HTTPClient for observer service of stand
+func (s Stand) NewHTTPClient() *HTTPClient {
+	return NewHTTPClient(s.ObserverAPIURL)
+}
+
+// NewObserverBatchResultSource - create source of batch results requesting ObserverBatchTxPath of observer service of stand,
+// returns error if path is not set in profile or env
+func (s Stand) NewObserverBatchResultSource() (*ObserverBatchResultSource, error) {
+	if s.ObserverBatchTxPath == "" {
+		return nil, errors.New("observer batch tx path of stand " + s.Name + " is not set")
+	}
+	return NewObserverBatchResultSource(s.ObserverAPIURL, s.ObserverBatchTxPath), nil
+}
+
+var (
+	standOnce    sync.Once
+	standLoaded  Stand
+	standLoadErr error
+)
+
+// standNetwork returns network of stand selected by env, stand is loaded once per process.
+// If StandProfile is not set helpers use DefaultNetwork without reading profiles as they did before stand profiles
+func standNetwork(t provider.T) Network {
+	if GetEnv(StandProfile, "") == "" {
+		return DefaultNetwork()
+	}
+	standOnce.Do(func() {
+		standLoaded, standLoadErr = GetStand()
+	})
+	t.Require().NoError(standLoadErr)
+	return standLoaded.Network
+}
@@ -0,0 +1,187 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStandWithCorrectNode(t *testing.T) {
+	tests := []struct {
+		name      string
+		node      string
+		endpoints []string
+	}{
+		{name: "not set", endpoints: []string{}},
+		{name: "set", node: "peer0", endpoints: []string{"peer0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stand := Stand{HlfProxyURL: "http://localhost:9001", CorrectNodeName: tt.node}
+			hlfProxy := stand.NewHlfProxyService(stand.WithCorrectNode())
+			require.Equal(t, tt.endpoints, hlfProxy.TargetEndpoints())
+			require.Equal(t, len(tt.endpoints) != 0, hlfProxy.request("acl", "checkKeys").Opts != nil)
+		})
+	}
+}
+
+const testProfiles = `{
+	"dev": {
+		"hlfProxyUrl": "http://dev:9001",
+		"hlfProxyAuthToken": "token",
+		"observerApiUrl": "http://dev:3305",
+		"observerBatchTxPath": "batch/transaction",
+		"correctNodeName": "peer0",
+		"endpoints": ["peer0", "peer1"],
+		"channels": {"fiat": {"name": "fiat-dev", "chaincode": "fiat", "ticker": "FIAT"}}
+	},
+	"stage": {
+		"hlfProxyUrl": "http://stage:9001",
+		"channels": {"fiat": {"name": "fiat", "chaincode": "fiat", "ticker": "FIAT", "issuerPrivateKey": "channelKey"}}
+	}
+}`
+
+// unsetStandEnv unsets env of stand for test, values are restored after test
+func unsetStandEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{
+		StandProfile, StandProfilesPath, HlfProxyURL, HlfProxyAuthToken,
+		FiatIssuerPrivateKey, ObserverAPIURL, ObserverBatchTxPath, CorrectNodeName, PeerEndpoints,
+	} {
+		t.Setenv(key, "")
+		require.NoError(t, os.Unsetenv(key))
+	}
+}
+
+func writeProfiles(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stands.json")
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+	return path
+}
+
+func TestLoadStands(t *testing.T) {
+	stands, err := LoadStands(writeProfiles(t, testProfiles))
+	require.NoError(t, err)
+	require.Len(t, stands, 2)
+	require.Equal(t, "dev", stands["dev"].Name)
+	require.Equal(t, "http://dev:9001", stands["dev"].HlfProxyURL)
+	require.Equal(t, []string{"peer0", "peer1"}, stands["dev"].Endpoints)
+	require.Equal(t, "fiat-dev", stands["dev"].Channel(ChannelFiat).Name)
+
+	_, err = LoadStands(filepath.Join(t.TempDir(), "missing.json"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = LoadStands(writeProfiles(t, "{"))
+	require.ErrorContains(t, err, "json unmarshal stand profiles")
+}
+
+func TestGetStand(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    Stand
+		wantErr string
+	}{
+		{
+			name: "local by default",
+			want: LocalStand(),
+		},
+		{
+			name: "profile from file",
+			env:  map[string]string{StandProfile: "dev"},
+			want: Stand{
+				Name:                "dev",
+				HlfProxyURL:         "http://dev:9001",
+				HlfProxyAuthToken:   "token",
+				ObserverAPIURL:      "http://dev:3305",
+				ObserverBatchTxPath: "batch/transaction",
+				CorrectNodeName:     "peer0",
+				Endpoints:           []string{"peer0", "peer1"},
+				Network:             Network{Channels: map[string]Channel{ChannelFiat: {Name: "fiat-dev", Chaincode: "fiat", Ticker: "FIAT"}}},
+			},
+		},
+		{
+			name: "env overrides profile",
+			env: map[string]string{
+				StandProfile:        "dev",
+				HlfProxyURL:         "http://env:9001",
+				HlfProxyAuthToken:   "envToken",
+				ObserverAPIURL:      "http://env:3305",
+				ObserverBatchTxPath: "api/tx",
+				CorrectNodeName:     "peer2",
+				PeerEndpoints:       "peer2,peer3",
+			},
+			want: Stand{
+				Name:                "dev",
+				HlfProxyURL:         "http://env:9001",
+				HlfProxyAuthToken:   "envToken",
+				ObserverAPIURL:      "http://env:3305",
+				ObserverBatchTxPath: "api/tx",
+				CorrectNodeName:     "peer2",
+				Endpoints:           []string{"peer2", "peer3"},
+				Network:             Network{Channels: map[string]Channel{ChannelFiat: {Name: "fiat-dev", Chaincode: "fiat", Ticker: "FIAT"}}},
+			},
+		},
+		{
+			name: "fiat issuer key is copied into fiat channel",
+			env:  map[string]string{StandProfile: "dev", FiatIssuerPrivateKey: "envKey"},
+			want: Stand{
+				Name:                 "dev",
+				HlfProxyURL:          "http://dev:9001",
+				HlfProxyAuthToken:    "token",
+				FiatIssuerPrivateKey: "envKey",
+				ObserverAPIURL:       "http://dev:3305",
+				ObserverBatchTxPath:  "batch/transaction",
+				CorrectNodeName:      "peer0",
+				Endpoints:            []string{"peer0", "peer1"},
+				Network: Network{Channels: map[string]Channel{
+					ChannelFiat: {Name: "fiat-dev", Chaincode: "fiat", Ticker: "FIAT", IssuerPrivateKey: "envKey"},
+				}},
+			},
+		},
+		{
+			name: "issuer key of fiat channel is kept",
+			env:  map[string]string{StandProfile: "stage", FiatIssuerPrivateKey: "envKey"},
+			want: Stand{
+				Name:                 "stage",
+				HlfProxyURL:          "http://stage:9001",
+				FiatIssuerPrivateKey: "envKey",
+				Network: Network{Channels: map[string]Channel{
+					ChannelFiat: {Name: "fiat", Chaincode: "fiat", Ticker: "FIAT", IssuerPrivateKey: "channelKey"},
+				}},
+			},
+		},
+		{
+			name:    "unknown profile",
+			env:     map[string]string{StandProfile: "prod"},
+			wantErr: "stand profile prod not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unsetStandEnv(t)
+			t.Setenv(StandProfilesPath, writeProfiles(t, testProfiles))
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			stand, err := GetStand()
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, stand)
+		})
+	}
+
+	t.Run("missing profiles file", func(t *testing.T) {
+		unsetStandEnv(t)
+		t.Setenv(StandProfilesPath, filepath.Join(t.TempDir(), "missing.json"))
+		_, err := GetStand()
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
@@ -1,109 +1,121 @@
 package transfer
 
 import (
+	"context"
 	"time"
 
 	utils "github.com/anoideaopen/testnet-util"
 	"github.com/ozontech/allure-go/pkg/framework/provider"
 )
 
-func ChannelTransferByCustomer(t provider.T, hlfProxy *utils.HlfProxyService, channelFrom string, user utils.User, transferArgs []string) {
+func ChannelTransferByCustomer(t provider.T, hlfProxy utils.ChaincodeClient, channelFrom string, user utils.User, transferArgs []string) {
 	t.WithNewStep("Signing transfer args and invoke channelTransferByCustomer then checking balance of head channel", func(sCtx provider.StepCtx) {
+		hlfProxy := utils.ClientInStep(hlfProxy, sCtx)
 		sa, err := utils.Sign(user.UserEd25519PrivateKey, user.UserEd25519PublicKey, channelFrom, channelFrom, "channelTransferByCustomer", transferArgs)
 		sCtx.Require().NoError(err)
 
-		_, err = hlfProxy.Invoke(channelFrom, "channelTransferByCustomer", sa...)
+		_, err = hlfProxy.InvokeContext(context.Background(), channelFrom, "channelTransferByCustomer", sa...)
 		sCtx.Require().NoError(err)
 
 		time.Sleep(utils.BatchTransactionTimeout)
 	})
 }
 
-func ChannelTransferByAdmin(t provider.T, hlfProxy *utils.HlfProxyService, issuer utils.Issuer, channelFrom string, transferArgs []string) {
+func ChannelTransferByAdmin(t provider.T, hlfProxy utils.ChaincodeClient, issuer utils.Issuer, channelFrom string, transferArgs []string) {
 	t.WithNewStep("Signing transfer args and invoke channelTransferByAdmin then checking balance of head channel", func(sCtx provider.StepCtx) {
+		hlfProxy := utils.ClientInStep(hlfProxy, sCtx)
 		sa, err := utils.Sign(issuer.IssuerEd25519PrivateKey, issuer.IssuerEd25519PublicKey, channelFrom, channelFrom, "channelTransferByAdmin", transferArgs)
 		sCtx.Require().NoError(err)
 
-		_, err = hlfProxy.Invoke(channelFrom, "channelTransferByAdmin", sa...)
+		_, err = hlfProxy.InvokeContext(context.Background(), channelFrom, "channelTransferByAdmin", sa...)
 		sCtx.Require().NoError(err)
 
 		time.Sleep(utils.BatchTransactionTimeout)
 	})
 }
 
-func ChannelTransferFrom(t provider.T, hlfProxy *utils.HlfProxyService, channel string, transferID string) string {
+func ChannelTransferFrom(t provider.T, hlfProxy utils.ChaincodeClient, channel string, transferID string) string {
 	var form string
 	t.WithNewStep("Getting a transfer record from outgoing channel with channelTransferFrom", func(sCtx provider.StepCtx) {
-		resp, err := hlfProxy.Invoke(channel, "channelTransferFrom", transferID)
+		hlfProxy := utils.ClientInStep(hlfProxy, sCtx)
+		resp, err := hlfProxy.InvokeContext(context.Background(), channel, "channelTransferFrom", transferID)
 		t.Require().NoError(err)
 		form = string(resp.Payload)
 	})
 	return form
 }
 
-func CreateCCTransferTo(t provider.T, hlfProxy *utils.HlfProxyService, channelTo string, form string) {
+func CreateCCTransferTo(t provider.T, hlfProxy utils.ChaincodeClient, channelTo string, form string) {
 	t.WithNewStep("create cc transfer", func(sCtx provider.StepCtx) {
+		hlfProxy := utils.ClientInStep(hlfProxy, sCtx)
 		transferArgs := []string{form}
-		_, err := hlfProxy.Invoke(channelTo, "createCCTransferTo", transferArgs...)
+		_, err := hlfProxy.InvokeContext(context.Background(), channelTo, "createCCTransferTo", transferArgs...)
 		t.Require().NoError(err)
 		time.Sleep(utils.BatchTransactionTimeout)
 	})
 }
 
-func ChannelTransferTo(t provider.T, hlfProxy *utils.HlfProxyService, channelTo string, transferID string) {
+func ChannelTransferTo(t provider.T, hlfProxy utils.ChaincodeClient, channelTo string, transferID string) {
 	t.WithNewStep("channel transfer", func(sCtx provider.StepCtx) {
+		hlfProxy := utils.ClientInStep(hlfProxy, sCtx)
 		a := []string{transferID}
-		_, err := hlfProxy.Invoke(channelTo, "channelTransferTo", a...)
+		_, err := hlfProxy.InvokeContext(context.Background(), channelTo, "channelTransferTo", a...)
 		t.Require().NoError(err)
 	})
 }
 
-func CheckAllowedBalanceEqual(t provider.T, hlfProxy *utils.HlfProxyService, userAddressBase58Check string, channel string, token string, amount string) {
+func CheckAllowedBalanceEqual(t provider.T, hlfProxy utils.ChaincodeClient, userAddressBase58Check string, channel string, token string, amount string) {
 	t.WithNewStep("Checking that balance equal "+amount, func(sCtx provider.StepCtx) {
-		respGetBalance, err := hlfProxy.Query(channel, "allowedBalanceOf", userAddressBase58Check, token)
+		hlfProxy := utils.ClientInStep(hlfProxy, sCtx)
+		respGetBalance, err := hlfProxy.QueryContext(context.Background(), channel, "allowedBalanceOf", userAddressBase58Check, token)
 		sCtx.Require().NoError(err)
 		sCtx.Require().Equal("\""+amount+"\"", string(respGetBalance.Payload))
 	})
 }
 
-func CommitCCTransferFrom(t provider.T, hlfProxy *utils.HlfProxyService, channelFrom string, transferID string) {
+func CommitCCTransferFrom(t provider.T, hlfProxy utils.ChaincodeClient, channelFrom string, transferID string) {
 	t.WithNewStep("commit CC transfer from", func(sCtx provider.StepCtx) {
+		hlfProxy := utils.ClientInStep(hlfProxy, sCtx)
 		a := []string{transferID}
-		_, err := hlfProxy.Invoke(channelFrom, "commitCCTransferFrom", a...)
+		_, err := hlfProxy.InvokeContext(context.Background(), channelFrom, "commitCCTransferFrom", a...)
 		t.Require().NoError(err)
 	})
 }
 
-func DeleteCCTransferTo(t provider.T, hlfProxy *utils.HlfProxyService, channelTo string, transferID string) {
+func DeleteCCTransferTo(t provider.T, hlfProxy utils.ChaincodeClient, channelTo string, transferID string) {
 	t.WithNewStep("dalete CC transfer to", func(sCtx provider.StepCtx) {
+		hlfProxy := utils.ClientInStep(hlfProxy, sCtx)
 		a := []string{transferID}
-		_, err := hlfProxy.Invoke(channelTo, "deleteCCTransferTo", a...)
+		_, err := hlfProxy.InvokeContext(context.Background(), channelTo, "deleteCCTransferTo", a...)
 		t.Require().NoError(err)
 	})
 }
 
-func DeleteCCTransferFrom(t provider.T, hlfProxy *utils.HlfProxyService, channelFrom string, transferID string) {
+func DeleteCCTransferFrom(t provider.T, hlfProxy utils.ChaincodeClient, channelFrom string, transferID string) {
 	t.WithNewStep("delete CC transfer from", func(sCtx provider.StepCtx) {
+		hlfProxy := utils.ClientInStep(hlfProxy, sCtx)
 		a := []string{transferID}
-		_, err := hlfProxy.Invoke(channelFrom, "deleteCCTransferFrom", a...)
+		_, err := hlfProxy.InvokeContext(context.Background(), channelFrom, "deleteCCTransferFrom", a...)
 		t.Require().NoError(err)
 	})
 }
 
-func CancelCCTransferFrom(t provider.T, hlfProxy *utils.HlfProxyService, channelFrom string, transferID string) {
+func CancelCCTransferFrom(t provider.T, hlfProxy utils.ChaincodeClient, channelFrom string, transferID string) {
 	t.WithNewStep("cancel CC transfer from", func(sCtx provider.StepCtx) {
+		hlfProxy := utils.ClientInStep(hlfProxy, sCtx)
 		a := []string{transferID}
-		_, err := hlfProxy.Invoke(channelFrom, "cancelCCTransferFrom", a...)
+		_, err := hlfProxy.InvokeContext(context.Background(), channelFrom, "cancelCCTransferFrom", a...)
 		t.Require().NoError(err)
 		time.Sleep(utils.BatchTransactionTimeout)
 	})
 }
 
-func ChannelTransfersFrom(t provider.T, hlfProxy *utils.HlfProxyService, channelFrom string, pageSize string, bookmark string) []byte {
+func ChannelTransfersFrom(t provider.T, hlfProxy utils.ChaincodeClient, channelFrom string, pageSize string, bookmark string) []byte {
 	var payload []byte
 	t.WithNewStep("channel transfer from", func(sCtx provider.StepCtx) {
+		hlfProxy := utils.ClientInStep(hlfProxy, sCtx)
 		a := []string{pageSize, bookmark}
-		resp, err := hlfProxy.Invoke(channelFrom, "channelTransfersFrom", a...)
+		resp, err := hlfProxy.InvokeContext(context.Background(), channelFrom, "channelTransfersFrom", a...)
 		t.Require().NoError(err)
 		payload = resp.Payload
 	})
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// TxValidationCode - validation code of fabric transaction, see peer.TxValidationCode in fabric-protos
+type TxValidationCode int64
+
+// Fabric transaction validation codes
+const (
+	TxValid                      TxValidationCode = 0
+	TxNilEnvelope                TxValidationCode = 1
+	TxBadPayload                 TxValidationCode = 2
+	TxBadCommonHeader            TxValidationCode = 3
+	TxBadCreatorSignature        TxValidationCode = 4
+	TxInvalidEndorserTransaction TxValidationCode = 5
+	TxInvalidConfigTransaction   TxValidationCode = 6
+	TxUnsupportedTxPayload       TxValidationCode = 7
+	TxBadProposalTxID            TxValidationCode = 8
+	TxDuplicateTxID              TxValidationCode = 9
+	TxEndorsementPolicyFailure   TxValidationCode = 10
+	TxMvccReadConflict           TxValidationCode = 11
+	TxPhantomReadConflict        TxValidationCode = 12
+	TxUnknownTxType              TxValidationCode = 13
+	TxTargetChainNotFound        TxValidationCode = 14
+	TxMarshalTxError             TxValidationCode = 15
+	TxNilTxAction                TxValidationCode = 16
+	TxExpiredChaincode           TxValidationCode = 17
+	TxChaincodeVersionConflict   TxValidationCode = 18
+	TxBadHeaderExtension         TxValidationCode = 19
+	TxBadChannelHeader           TxValidationCode = 20
+	TxBadResponsePayload         TxValidationCode = 21
+	TxBadRwset                   TxValidationCode = 22
+	TxIllegalWriteset            TxValidationCode = 23
+	TxInvalidWriteset            TxValidationCode = 24
+	TxInvalidChaincode           TxValidationCode = 25
+	TxNotValidated               TxValidationCode = 254
+	TxInvalidOtherReason         TxValidationCode = 255
+)
+
+// ChaincodeStatusErrorThreshold - chaincode response status starting from which response is an error, see shim.ERRORTHRESHOLD
+const ChaincodeStatusErrorThreshold = 400
+
+// ErrTxNotValid - transaction was not committed as VALID or chaincode returned error status
+var ErrTxNotValid = errors.New("transaction is not valid")
+
+var txValidationCodeNames = map[TxValidationCode]string{
+	TxValid:                      "VALID",
+	TxNilEnvelope:                "NIL_ENVELOPE",
+	TxBadPayload:                 "BAD_PAYLOAD",
+	TxBadCommonHeader:            "BAD_COMMON_HEADER",
+	TxBadCreatorSignature:        "BAD_CREATOR_SIGNATURE",
+	TxInvalidEndorserTransaction: "INVALID_ENDORSER_TRANSACTION",
+	TxInvalidConfigTransaction:   "INVALID_CONFIG_TRANSACTION",
+	TxUnsupportedTxPayload:       "UNSUPPORTED_TX_PAYLOAD",
+	TxBadProposalTxID:            "BAD_PROPOSAL_TXID",
+	TxDuplicateTxID:              "DUPLICATE_TXID",
+	TxEndorsementPolicyFailure:   "ENDORSEMENT_POLICY_FAILURE",
+	TxMvccReadConflict:           "MVCC_READ_CONFLICT",
+	TxPhantomReadConflict:        "PHANTOM_READ_CONFLICT",
+	TxUnknownTxType:              "UNKNOWN_TX_TYPE",
+	TxTargetChainNotFound:        "TARGET_CHAIN_NOT_FOUND",
+	TxMarshalTxError:             "MARSHAL_TX_ERROR",
+	TxNilTxAction:                "NIL_TXACTION",
+	TxExpiredChaincode:           "EXPIRED_CHAINCODE",
+	TxChaincodeVersionConflict:   "CHAINCODE_VERSION_CONFLICT",
+	TxBadHeaderExtension:         "BAD_HEADER_EXTENSION",
+	TxBadChannelHeader:           "BAD_CHANNEL_HEADER",
+	TxBadResponsePayload:         "BAD_RESPONSE_PAYLOAD",
+	TxBadRwset:                   "BAD_RWSET",
+	TxIllegalWriteset:            "ILLEGAL_WRITESET",
+	TxInvalidWriteset:            "INVALID_WRITESET",
+	TxInvalidChaincode:           "INVALID_CHAINCODE",
+	TxNotValidated:               "NOT_VALIDATED",
+	TxInvalidOtherReason:         "INVALID_OTHER_REASON",
+}
+
+// String returns fabric name of validation code, example MVCC_READ_CONFLICT
+func (c TxValidationCode) String() string {
+	if name, ok := txValidationCodeNames[c]; ok {
+		return name
+	}
+	return "UNKNOWN(" + strconv.FormatInt(int64(c), 10) + ")"
+}
+
+// ValidationCode returns validation code of transaction
+func (r *Response) ValidationCode() TxValidationCode {
+	return TxValidationCode(r.TxValidationCode)
+}
+
+// IsValid returns true if transaction was committed as VALID and chaincode did not return error status
+func (r *Response) IsValid() bool {
+	return r.CheckValid() == nil
+}
+
+// CheckValid returns descriptive error if transaction was not committed as VALID or chaincode returned error status
+func (r *Response) CheckValid() error {
+	return checkTxValid(r.TransactionID, r.TxValidationCode, r.ChaincodeStatus)
+}
+
+// ValidationCode returns validation code of transaction
+func (r *ResponsePrx) ValidationCode() TxValidationCode {
+	return TxValidationCode(r.TxValidationCode)
+}
+
+// IsValid returns true if transaction was committed as VALID and chaincode did not return error status
+func (r *ResponsePrx) IsValid() bool {
+	return r.CheckValid() == nil
+}
+
+// CheckValid returns descriptive error if transaction was not committed as VALID or chaincode returned error status
+func (r *ResponsePrx) CheckValid() error {
+	return checkTxValid(r.TransactionID, r.TxValidationCode, r.ChaincodeStatus)
+}
+
+func checkTxValid(txID string, validationCode int64, chaincodeStatus int64) error {
+	if code := TxValidationCode(validationCode); code != TxValid {
+		return fmt.Errorf("%w: tx %s validation code %s", ErrTxNotValid, txID, code)
+	}
+	if chaincodeStatus >= ChaincodeStatusErrorThreshold {
+		return fmt.Errorf("%w: tx %s chaincode status %d", ErrTxNotValid, txID, chaincodeStatus)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCheckValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		response Response
+		valid    bool
+	}{
+		{name: "valid", response: Response{TransactionID: "tx", ChaincodeStatus: 200}, valid: true},
+		{name: "valid without chaincode status", response: Response{TransactionID: "tx"}, valid: true},
+		{name: "mvcc read conflict", response: Response{TransactionID: "tx", TxValidationCode: int64(TxMvccReadConflict)}},
+		{name: "chaincode error", response: Response{TransactionID: "tx", ChaincodeStatus: 500}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.response.CheckValid()
+			require.Equal(t, tt.valid, tt.response.IsValid())
+			if tt.valid {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrTxNotValid)
+			require.Contains(t, err.Error(), tt.response.TransactionID)
+		})
+	}
+}
+
+func TestTxValidationCodeString(t *testing.T) {
+	tests := []struct {
+		code TxValidationCode
+		name string
+	}{
+		{code: TxValid, name: "VALID"},
+		{code: TxMvccReadConflict, name: "MVCC_READ_CONFLICT"},
+		{code: TxInvalidOtherReason, name: "INVALID_OTHER_REASON"},
+		{code: 100, name: "UNKNOWN(100)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.name, tt.code.String())
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"context"
+	"errors"
 	"time"
 
 	"github.com/btcsuite/btcutil/base58"
@@ -23,21 +25,52 @@ type User struct {
 	UserAddressBase58Check string
 }
 
-// AddIssuer adds issuer
-func AddIssuer(t provider.T, hlfProxy HlfProxyService, base58Check string) Issuer {
+// NewIssuer creates issuer by private key ed25519 in base58 check without adding it in acl
+func NewIssuer(base58Check string) (Issuer, error) {
+	privateKey, publicKey, err := GetPrivateKeyFromBase58Check(base58Check)
+	if err != nil {
+		return Issuer{}, err
+	}
+	return Issuer{privateKey, publicKey, base58.Encode(publicKey)}, nil
+}
+
+// NewUser creates user by private key ed25519 without adding it in acl
+func NewUser(privateKey ed25519.PrivateKey) (User, error) {
+	publicKey, ok := privateKey.Public().(ed25519.PublicKey)
+	if !ok {
+		return User{}, errors.New("type assertion failed")
+	}
+	address, err := GetAddressByPublicKey(publicKey)
+	if err != nil {
+		return User{}, err
+	}
+	return User{privateKey, publicKey, base58.Encode(publicKey), address}, nil
+}
+
+// AddIssuer adds issuer in acl channel of stand selected by env
+func AddIssuer(t provider.T, hlfProxy ChaincodeClient, base58Check string) Issuer {
+	return AddIssuerToNetwork(t, hlfProxy, standNetwork(t), base58Check)
+}
+
+// AddIssuerToNetwork adds issuer in acl channel of network
+func AddIssuerToNetwork(t provider.T, hlfProxy ChaincodeClient, network Network, base58Check string) Issuer {
 	var issuerFiatEd25519PrivateKey ed25519.PrivateKey
 	var issuerFiatEd25519PublicKey ed25519.PublicKey
 	var err error
 	var issuerEd25519PublicKeyBase58 string
+	acl := network.Channel(ChannelACL)
 
 	t.WithNewStep("Generate cryptos for issuer", func(sCtx provider.StepCtx) {
-		issuerFiatEd25519PrivateKey, issuerFiatEd25519PublicKey, err = GetPrivateKeyFromBase58Check(base58Check)
+		issuer, err := NewIssuer(base58Check)
 		sCtx.Require().NoError(err)
-		issuerEd25519PublicKeyBase58 = base58.Encode(issuerFiatEd25519PublicKey)
+		issuerFiatEd25519PrivateKey = issuer.IssuerEd25519PrivateKey
+		issuerFiatEd25519PublicKey = issuer.IssuerEd25519PublicKey
+		issuerEd25519PublicKeyBase58 = issuer.IssuerEd25519PublicKeyBase58
 	})
 
 	t.WithNewStep("Add issuer. Try to add issuer user in acl, issuer may already exist", func(sCtx provider.StepCtx) {
-		_, err = hlfProxy.Invoke("acl", "addUser", issuerEd25519PublicKeyBase58, "test", "testuser", "true")
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		_, err = hlfProxy.InvokeContext(context.Background(), acl.Name, "addUser", issuerEd25519PublicKeyBase58, "test", "testuser", "true")
 		if err != nil {
 			sCtx.Require().Contains(err.Error(), "already exists")
 			return
@@ -47,19 +80,26 @@ func AddIssuer(t provider.T, hlfProxy HlfProxyService, base58Check string) Issue
 	time.Sleep(BatchTransactionTimeout)
 
 	t.WithNewStep("Check user is created by querying method `checkKeys` of chaincode `acl`", func(sCtx provider.StepCtx) {
-		_, err = hlfProxy.Query("acl", "checkKeys", issuerEd25519PublicKeyBase58)
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		_, err = hlfProxy.QueryContext(context.Background(), acl.Name, "checkKeys", issuerEd25519PublicKeyBase58)
 		sCtx.Require().NoError(err)
 	})
 	return Issuer{issuerFiatEd25519PrivateKey, issuerFiatEd25519PublicKey, issuerEd25519PublicKeyBase58}
 }
 
-// AddUser adds user
-func AddUser(t provider.T, hlfProxy HlfProxyService) User {
+// AddUser adds user in acl channel of stand selected by env
+func AddUser(t provider.T, hlfProxy ChaincodeClient) User {
+	return AddUserToNetwork(t, hlfProxy, standNetwork(t))
+}
+
+// AddUserToNetwork adds user in acl channel of network
+func AddUserToNetwork(t provider.T, hlfProxy ChaincodeClient, network Network) User {
 	var userEd25519PrivateKey ed25519.PrivateKey
 	var userEd25519PublicKey ed25519.PublicKey
 	var err error
 	var userPublicKeyBase58 string
 	var userAddressBase58Check string
+	acl := network.Channel(ChannelACL)
 
 	t.WithNewStep("Generate cryptos for user", func(sCtx provider.StepCtx) {
 		userEd25519PrivateKey, userEd25519PublicKey, err = GeneratePrivateAndPublicKey()
@@ -70,7 +110,8 @@ func AddUser(t provider.T, hlfProxy HlfProxyService) User {
 	})
 
 	t.WithNewStep("Add user by invoking method `addUser` of chaincode `acl` with valid parameters", func(sCtx provider.StepCtx) {
-		res, err := hlfProxy.Invoke("acl", "addUser", userPublicKeyBase58, "test", "testuser", "true")
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		res, err := hlfProxy.InvokeContext(context.Background(), acl.Name, "addUser", userPublicKeyBase58, "test", "testuser", "true")
 		sCtx.Require().NoError(err)
 		sCtx.Require().NotNil(res)
 	})
@@ -78,13 +119,62 @@ func AddUser(t provider.T, hlfProxy HlfProxyService) User {
 	time.Sleep(BatchTransactionTimeout)
 
 	t.WithNewStep("Check user is created by querying method `checkKeys` of chaincode `acl`", func(sCtx provider.StepCtx) {
-		_, err = hlfProxy.Query("acl", "checkKeys", userPublicKeyBase58)
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		_, err = hlfProxy.QueryContext(context.Background(), acl.Name, "checkKeys", userPublicKeyBase58)
 		sCtx.Require().NoError(err)
 	})
 
 	return User{userEd25519PrivateKey, userEd25519PublicKey, userPublicKeyBase58, userAddressBase58Check}
 }
 
+// AddUserWithPrivateKey adds user with private key in acl channel of stand selected by env, user may already exist
+func AddUserWithPrivateKey(t provider.T, hlfProxy ChaincodeClient, privateKey ed25519.PrivateKey) User {
+	return AddUserWithPrivateKeyToNetwork(t, hlfProxy, standNetwork(t), privateKey)
+}
+
+// AddUserWithPrivateKeyToNetwork adds user with private key in acl channel of network, user may already exist
+func AddUserWithPrivateKeyToNetwork(t provider.T, hlfProxy ChaincodeClient, network Network, privateKey ed25519.PrivateKey) User {
+	var (
+		user User
+		err  error
+	)
+	acl := network.Channel(ChannelACL)
+
+	t.WithNewStep("Restore cryptos for user from private key", func(sCtx provider.StepCtx) {
+		user, err = NewUser(privateKey)
+		sCtx.Require().NoError(err)
+	})
+
+	t.WithNewStep("Add user. Try to add user in acl, user may already exist", func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		_, err = hlfProxy.InvokeContext(context.Background(), acl.Name, "addUser", user.UserPublicKeyBase58, "test", "testuser", "true")
+		if err != nil {
+			sCtx.Require().Contains(err.Error(), "already exists")
+			return
+		}
+	})
+
+	time.Sleep(BatchTransactionTimeout)
+
+	t.WithNewStep("Check user is created by querying method `checkKeys` of chaincode `acl`", func(sCtx provider.StepCtx) {
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		_, err = hlfProxy.QueryContext(context.Background(), acl.Name, "checkKeys", user.UserPublicKeyBase58)
+		sCtx.Require().NoError(err)
+	})
+
+	return user
+}
+
+// RegisterUser invokes method `addUser` of chaincode `acl` in network for user without waiting for batch execution
+func RegisterUser(hlfProxy ChaincodeClient, network Network, user User) (*Response, error) {
+	return RegisterUserContext(context.Background(), hlfProxy, network, user)
+}
+
+// RegisterUserContext - RegisterUser with context
+func RegisterUserContext(ctx context.Context, hlfProxy ChaincodeClient, network Network, user User) (*Response, error) {
+	return hlfProxy.InvokeContext(ctx, network.Channel(ChannelACL).Name, "addUser", user.UserPublicKeyBase58, "test", "testuser", "true")
+}
+
 // GenerateUserPublicKeyBase58 generates user public key base58
 func GenerateUserPublicKeyBase58(t provider.T) string {
 	var userEd25519PublicKey ed25519.PublicKey
@@ -101,8 +191,13 @@ func GenerateUserPublicKeyBase58(t provider.T) string {
 	return userPublicKeyBase58
 }
 
-// AddUserGetResponce adds user and returns response
-func AddUserGetResponce(t provider.T, hlfProxy HlfProxyService) (User, *Response) {
+// AddUserGetResponce adds user in acl channel of stand selected by env and returns response
+func AddUserGetResponce(t provider.T, hlfProxy ChaincodeClient) (User, *Response) {
+	return AddUserToNetworkGetResponse(t, hlfProxy, standNetwork(t))
+}
+
+// AddUserToNetworkGetResponse adds user in acl channel of network and returns response
+func AddUserToNetworkGetResponse(t provider.T, hlfProxy ChaincodeClient, network Network) (User, *Response) {
 	var (
 		userEd25519PrivateKey  ed25519.PrivateKey
 		userEd25519PublicKey   ed25519.PublicKey
@@ -111,6 +206,7 @@ func AddUserGetResponce(t provider.T, hlfProxy HlfProxyService) (User, *Response
 		userAddressBase58Check string
 		res                    *Response
 	)
+	acl := network.Channel(ChannelACL)
 
 	t.WithNewStep("Generate cryptos for user", func(sCtx provider.StepCtx) {
 		userEd25519PrivateKey, userEd25519PublicKey, err = GeneratePrivateAndPublicKey()
@@ -121,14 +217,16 @@ func AddUserGetResponce(t provider.T, hlfProxy HlfProxyService) (User, *Response
 	})
 
 	t.WithNewStep("Add user by invoking method `addUser` of chaincode `acl` with valid parameters", func(sCtx provider.StepCtx) {
-		res, err = hlfProxy.Invoke("acl", "addUser", userPublicKeyBase58, "test", "testuser", "true")
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		res, err = hlfProxy.InvokeContext(context.Background(), acl.Name, "addUser", userPublicKeyBase58, "test", "testuser", "true")
 		sCtx.Require().NoError(err)
 		sCtx.Require().NotNil(res)
 	})
 
 	time.Sleep(BatchTransactionTimeout)
 	t.WithNewStep("Check user is created by querying method `checkKeys` of chaincode `acl`", func(sCtx provider.StepCtx) {
-		_, err = hlfProxy.Query("acl", "checkKeys", userPublicKeyBase58)
+		hlfProxy := ClientInStep(hlfProxy, sCtx)
+		_, err = hlfProxy.QueryContext(context.Background(), acl.Name, "checkKeys", userPublicKeyBase58)
 		sCtx.Require().NoError(err)
 	})
 
@@ -25,8 +25,12 @@ const (
 	BatchTransactionTimeout = 2 * time.Second
 	// ObserverAPIURL - domain and port for observer service, example http://localhost:3335/api without '/' on the end the string
 	ObserverAPIURL = "OBSERVER_API_URL"
+	// ObserverTxURL - url of observer web page of transaction, tx id is appended to it, example http://localhost:3000/transaction
+	ObserverTxURL = "OBSERVER_TX_URL"
 	// CorrectNodeName Name of any node from stand
 	CorrectNodeName = "CORRECT_NODE_NAME"
+	// PeerEndpoints - comma separated names of all peers of stand, example peer0.org1,peer0.org2
+	PeerEndpoints = "PEER_ENDPOINTS"
 	// DefaultSwapHash - default swap hash
 	DefaultSwapHash = "7d4e3eec80026719639ed4dba68916eb94c7a49a053e05c8f9578fe4e5a3d7ea" // #nosec G101
 	// DefaultSwapKey - default swap key